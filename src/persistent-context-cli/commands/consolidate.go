@@ -7,11 +7,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/JaimeStill/persistent-context/persistent-context-cli/pkg"
+	"github.com/JaimeStill/persistent-context/pkg/models"
 )
 
 var (
-	batchSize   int
-	progressive bool
+	batchSize    int
+	progressive  bool
+	pollInterval time.Duration
 )
 
 var consolidateCmd = &cobra.Command{
@@ -41,38 +43,50 @@ optimal performance parameters.`,
 			}
 		}
 		
+		request := &models.ConsolidateRequest{BatchSize: batchSize}
 		if progressive {
 			fmt.Println("\nTesting progressive consolidation strategy...")
 			// For now, we'll just trigger consolidation and measure time
 			// In the future, this would test different progressive strategies
-		} else {
+		} else if batchSize > 0 {
 			fmt.Printf("\nTesting consolidation with batch size: %d\n", batchSize)
-			fmt.Println("Note: Batch size testing requires direct pkg access (not yet implemented)")
-			fmt.Println("Currently using web service's autonomous consolidation...")
+			fmt.Println("Groups larger than the batch size are consolidated in batches and then merged")
+		} else {
+			fmt.Println("\nTesting consolidation with token-bounded batches...")
 		}
 		
-		// Trigger consolidation and measure time
+		// Start consolidation and poll the job until it finishes
 		fmt.Println("\nTriggering consolidation...")
 		start := time.Now()
 		
-		result, err := client.TriggerConsolidation()
+		started, err := client.TriggerConsolidation(request)
+		if err != nil {
+			return fmt.Errorf("failed to start consolidation: %w", err)
+		}
+		fmt.Printf("Job %s started\n", started.JobID)
+		
+		result, err := waitForJob(client, started.JobID)
 		if err != nil {
-			duration := time.Since(start)
-			return fmt.Errorf("consolidation failed after %v: %w", duration, err)
+			return err
 		}
 		
 		duration := time.Since(start)
+		if result.Status != models.JobCompleted {
+			printJob(result)
+			return fmt.Errorf("consolidation %s after %v: %s", result.Status, duration, result.Error)
+		}
 		
 		// Display results
 		fmt.Printf("\nConsolidation completed in: %v\n", duration)
-		fmt.Printf("Groups formed: %d\n", result.GroupsFormed)
-		fmt.Printf("Groups consolidated: %d\n", result.GroupsConsolidated)
-		fmt.Printf("Memories processed: %d\n", result.MemoriesProcessed)
-		fmt.Printf("Total memories: %d\n", result.TotalMemories)
+		fmt.Printf("Groups formed: %d\n", result.Progress.GroupsFormed)
+		fmt.Printf("Groups consolidated: %d\n", result.Progress.GroupsConsolidated)
+		fmt.Printf("Groups failed: %d\n", result.Progress.GroupsFailed)
+		fmt.Printf("Memories processed: %d\n", result.Progress.MemoriesProcessed)
+		fmt.Printf("Total memories: %d\n", result.Progress.TotalMemories)
 		
 		// Analyze performance
-		if result.GroupsFormed > 0 {
-			avgGroupSize := float64(result.MemoriesProcessed) / float64(result.GroupsFormed)
+		if result.Progress.GroupsFormed > 0 {
+			avgGroupSize := float64(result.Progress.MemoriesProcessed) / float64(result.Progress.GroupsFormed)
 			fmt.Printf("\nAverage group size: %.1f memories\n", avgGroupSize)
 			
 			if duration > 30*time.Second {
@@ -85,10 +99,109 @@ optimal performance parameters.`,
 	},
 }
 
+var consolidateStatusCmd = &cobra.Command{
+	Use:   "status <job-id>",
+	Short: "Show a consolidation job's progress",
+	Long:  `Show the status, per-group progress, errors and created semantic memories of a consolidation job.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
+		
+		job, err := client.GetJob(args[0])
+		if err != nil {
+			return err
+		}
+		
+		printJob(job)
+		return nil
+	},
+}
+
+var consolidateCancelCmd = &cobra.Command{
+	Use:   "cancel <job-id>",
+	Short: "Cancel a running consolidation job",
+	Long:  `Cancel a running consolidation job. Groups that were already consolidated are kept.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
+		
+		job, err := client.CancelJob(args[0])
+		if err != nil {
+			return err
+		}
+		
+		fmt.Printf("Cancellation requested for job %s\n", job.ID)
+		return nil
+	},
+}
+
+// waitForJob polls a job until it finishes, printing progress as groups complete
+func waitForJob(client *pkg.Client, id string) (*models.Job, error) {
+	completed := -1
+	for {
+		job, err := client.GetJob(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to poll job: %w", err)
+		}
+		
+		if job.Progress.GroupsCompleted != completed {
+			completed = job.Progress.GroupsCompleted
+			fmt.Printf("  %s: %d/%d groups completed\n", job.Status, completed, job.Progress.GroupsFormed)
+		}
+		
+		if job.Status.Finished() {
+			return job, nil
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// printJob displays a job record with its groups
+func printJob(job *models.Job) {
+	fmt.Printf("Job: %s (%s)\n", job.ID, job.Type)
+	fmt.Printf("Status: %s\n", job.Status)
+	if job.Message != "" {
+		fmt.Printf("Message: %s\n", job.Message)
+	}
+	if job.Error != "" {
+		fmt.Printf("Error: %s\n", job.Error)
+	}
+	fmt.Printf("Created: %s\n", job.CreatedAt.Format(time.RFC3339))
+	if job.CompletedAt != nil {
+		fmt.Printf("Completed: %s\n", job.CompletedAt.Format(time.RFC3339))
+	}
+	
+	fmt.Printf("\nGroups: %d/%d completed, %d consolidated, %d failed\n",
+		job.Progress.GroupsCompleted, job.Progress.GroupsFormed,
+		job.Progress.GroupsConsolidated, job.Progress.GroupsFailed)
+	for i, group := range job.Groups {
+		switch {
+		case group.Skipped:
+			fmt.Printf("  [%d] skipped (%d memory)\n", i+1, len(group.MemoryIDs))
+		case group.Error != "":
+			fmt.Printf("  [%d] %s (%d memories): %s\n", i+1, group.Status, len(group.MemoryIDs), group.Error)
+		case group.SemanticID != "":
+			fmt.Printf("  [%d] %s (%d memories) -> %s\n", i+1, group.Status, len(group.MemoryIDs), group.SemanticID)
+		default:
+			fmt.Printf("  [%d] %s (%d memories)\n", i+1, group.Status, len(group.MemoryIDs))
+		}
+	}
+	
+	if len(job.SemanticIDs) > 0 {
+		fmt.Printf("\nSemantic memories created: %d\n", len(job.SemanticIDs))
+		for _, id := range job.SemanticIDs {
+			fmt.Printf("  %s\n", id)
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(consolidateCmd)
 	consolidateCmd.AddCommand(consolidateTestCmd)
+	consolidateCmd.AddCommand(consolidateStatusCmd)
+	consolidateCmd.AddCommand(consolidateCancelCmd)
 	
-	consolidateTestCmd.Flags().IntVar(&batchSize, "batch-size", 3, "Number of memories to consolidate per batch")
+	consolidateTestCmd.Flags().IntVar(&batchSize, "batch-size", 3, "Maximum memories per consolidation prompt (0 uses the service's token budget)")
 	consolidateTestCmd.Flags().BoolVar(&progressive, "progressive", false, "Use progressive consolidation strategy")
+	consolidateTestCmd.Flags().DurationVar(&pollInterval, "poll-interval", time.Second, "How often to poll the consolidation job")
 }
\ No newline at end of file
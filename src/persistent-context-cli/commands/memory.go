@@ -3,11 +3,18 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/JaimeStill/persistent-context/persistent-context-cli/pkg"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+var (
+	showAssociations bool
+	showSources      bool
 )
 
 var memoryCmd = &cobra.Command{
@@ -62,7 +69,7 @@ var memoryShowCmd = &cobra.Command{
 		memoryID := args[0]
 		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
 		
-		memory, err := client.GetMemory(memoryID)
+		memory, err := client.GetMemory(memoryID, showAssociations, showSources)
 		if err != nil {
 			return fmt.Errorf("failed to get memory: %w", err)
 		}
@@ -88,12 +95,125 @@ var memoryShowCmd = &cobra.Command{
 			}
 		}
 		
+		if showAssociations && len(memory.Associations) > 0 {
+			fmt.Printf("\nAssociation Details:\n")
+			for _, assoc := range memory.Associations {
+				fmt.Printf("  - %s -> %s (%s, strength %.2f)\n", assoc.SourceID, assoc.TargetID, assoc.Type, assoc.Strength)
+			}
+		}
+		
+		if showSources && len(memory.Sources) > 0 {
+			fmt.Printf("\nConsolidated From: %d\n", len(memory.Sources))
+			for _, source := range memory.Sources {
+				preview := source.Content
+				if len(preview) > 50 {
+					preview = preview[:47] + "..."
+				}
+				fmt.Printf("  - %s [%s] %s\n", source.ID, source.Type, preview)
+			}
+		}
+		
 		return nil
 	},
 }
 
+var memoryDeleteCmd = &cobra.Command{
+	Use:   "delete <memory-id>...",
+	Short: "Delete memories",
+	Long:  `Permanently delete memories along with their associations, for example ones that captured secrets or wrong conclusions.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
+		
+		for _, memoryID := range args {
+			result, err := client.DeleteMemory(memoryID)
+			if err != nil {
+				return fmt.Errorf("failed to delete memory: %w", err)
+			}
+			
+			fmt.Printf("Deleted %s (%d associations removed, %d neighbors updated)\n",
+				result.ID, result.AssociationsRemoved, result.NeighborsUpdated)
+		}
+		
+		return nil
+	},
+}
+
+var memoryLineageCmd = &cobra.Command{
+	Use:   "lineage <memory-id>",
+	Short: "Show a memory's consolidation lineage",
+	Long:  `Walk a consolidated memory back through every consolidation to the episodes it was derived from.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
+		
+		lineage, err := client.GetLineage(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to get lineage: %w", err)
+		}
+		
+		printLineage(lineage, 0)
+		return nil
+	},
+}
+
+var memoryRollbackCmd = &cobra.Command{
+	Use:   "rollback <memory-id>",
+	Short: "Undo a consolidation",
+	Long: `Delete a consolidated memory, for example a hallucinated one, and return its source
+memories to unconsolidated so they can be consolidated again.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := pkg.NewClient(viper.GetString("web_url"), viper.GetDuration("timeout"))
+		
+		result, err := client.RollbackConsolidation(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to roll back consolidation: %w", err)
+		}
+		
+		fmt.Printf("Deleted %s\n", result.ConsolidatedID)
+		fmt.Printf("Sources restored: %d\n", len(result.Restored))
+		if len(result.StillConsolidated) > 0 {
+			fmt.Printf("Sources still consolidated elsewhere: %d\n", len(result.StillConsolidated))
+		}
+		if len(result.Missing) > 0 {
+			fmt.Printf("Sources already deleted: %d\n", len(result.Missing))
+		}
+		if len(result.Unaffected) > 0 {
+			fmt.Printf("Sources not consolidated into it: %d\n", len(result.Unaffected))
+		}
+		
+		return nil
+	},
+}
+
+// printLineage prints a lineage tree, indenting each level of sources
+func printLineage(node *models.LineageNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if node.Missing {
+		fmt.Printf("%s- %s (deleted)\n", indent, node.ID)
+		return
+	}
+	
+	preview := node.Memory.Content
+	if len(preview) > 50 {
+		preview = preview[:47] + "..."
+	}
+	fmt.Printf("%s- %s [%s] %s\n", indent, node.ID, node.Memory.Type, preview)
+	
+	for _, source := range node.Sources {
+		printLineage(source, depth+1)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(memoryCmd)
 	memoryCmd.AddCommand(memoryListCmd)
 	memoryCmd.AddCommand(memoryShowCmd)
+	memoryCmd.AddCommand(memoryDeleteCmd)
+	memoryCmd.AddCommand(memoryLineageCmd)
+	memoryCmd.AddCommand(memoryRollbackCmd)
+	
+	memoryShowCmd.Flags().BoolVar(&showAssociations, "associations", false, "Include association details")
+	memoryShowCmd.Flags().BoolVar(&showSources, "sources", false, "Include the memories this one was consolidated from")
 }
\ No newline at end of file
@@ -53,9 +53,10 @@ func (c *Client) GetMemories(limit int) ([]*models.MemoryEntry, error) {
 	return response.Memories, nil
 }
 
-// GetMemory retrieves a specific memory by ID
-func (c *Client) GetMemory(id string) (*models.MemoryEntry, error) {
-	url := fmt.Sprintf("%s/api/v1/journal/%s", c.baseURL, id)
+// GetMemory retrieves a specific memory by ID, optionally with its associations and consolidation sources
+func (c *Client) GetMemory(id string, includeAssociations, includeSources bool) (*models.GetMemoryResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/%s?include_associations=%t&include_sources=%t",
+		c.baseURL, id, includeAssociations, includeSources)
 
 	resp, err := c.httpClient.Get(url)
 	if err != nil {
@@ -72,7 +73,7 @@ func (c *Client) GetMemory(id string) (*models.MemoryEntry, error) {
 		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
 	}
 
-	var memory models.MemoryEntry
+	var memory models.GetMemoryResponse
 	if err := json.NewDecoder(resp.Body).Decode(&memory); err != nil {
 		return nil, fmt.Errorf("failed to decode memory: %w", err)
 	}
@@ -80,21 +81,112 @@ func (c *Client) GetMemory(id string) (*models.MemoryEntry, error) {
 	return &memory, nil
 }
 
-// TriggerConsolidation triggers the consolidation process
-func (c *Client) TriggerConsolidation() (*models.ConsolidateResponse, error) {
-	url := fmt.Sprintf("%s/api/v1/journal/consolidate", c.baseURL)
+// DeleteMemory permanently deletes a memory and its associations
+func (c *Client) DeleteMemory(id string) (*models.DeleteMemoryResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/%s", c.baseURL, id)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete memory: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("memory not found: %s", id)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response models.DeleteMemoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &response, nil
+}
+
+// GetLineage retrieves the consolidation lineage of a memory
+func (c *Client) GetLineage(id string) (*models.LineageNode, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/%s/lineage", c.baseURL, id)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lineage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("memory not found: %s", id)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var lineage models.LineageNode
+	if err := json.NewDecoder(resp.Body).Decode(&lineage); err != nil {
+		return nil, fmt.Errorf("failed to decode lineage: %w", err)
+	}
+
+	return &lineage, nil
+}
+
+// RollbackConsolidation deletes a consolidated memory and restores its sources
+func (c *Client) RollbackConsolidation(id string) (*models.RollbackResult, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/%s/rollback", c.baseURL, id)
 
 	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader([]byte("{}")))
 	if err != nil {
-		return nil, fmt.Errorf("failed to trigger consolidation: %w", err)
+		return nil, fmt.Errorf("failed to roll back consolidation: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("memory not found: %s", id)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
 	}
 
+	var result models.RollbackResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &result, nil
+}
+
+// TriggerConsolidation starts a consolidation job
+func (c *Client) TriggerConsolidation(req *models.ConsolidateRequest) (*models.ConsolidateResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/consolidate", c.baseURL)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger consolidation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
 	var response models.ConsolidateResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
@@ -103,6 +195,60 @@ func (c *Client) TriggerConsolidation() (*models.ConsolidateResponse, error) {
 	return &response, nil
 }
 
+// GetJob retrieves a background job's status and progress
+func (c *Client) GetJob(id string) (*models.Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs/%s", c.baseURL, id)
+
+	resp, err := c.httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("job not found: %s", id)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+
+	return &job, nil
+}
+
+// CancelJob requests cancellation of a background job
+func (c *Client) CancelJob(id string) (*models.Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs/%s/cancel", c.baseURL, id)
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader([]byte("{}")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("job not found: %s", id)
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode job: %w", err)
+	}
+
+	return &job, nil
+}
+
 // GetStats retrieves system statistics
 func (c *Client) GetStats() (*models.StatsResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/journal/stats", c.baseURL)
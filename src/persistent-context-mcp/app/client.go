@@ -101,14 +101,8 @@ func (c *Client) GetMemories(ctx context.Context, limit uint32) ([]*models.Memor
 }
 
 
-// QuerySimilarMemories searches for similar memories via HTTP API
-func (c *Client) QuerySimilarMemories(ctx context.Context, content string, memoryType models.MemoryType, limit uint64) ([]*models.MemoryEntry, error) {
-	req := models.SearchMemoriesRequest{
-		Content:    content,
-		MemoryType: string(memoryType),
-		Limit:      limit,
-	}
-
+// SearchMemories searches memories via HTTP API
+func (c *Client) SearchMemories(ctx context.Context, req models.SearchMemoriesRequest) ([]*models.MemoryEntry, error) {
 	reqBody, err := json.Marshal(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -140,7 +134,7 @@ func (c *Client) QuerySimilarMemories(ctx context.Context, content string, memor
 	return searchResp.Memories, nil
 }
 
-// TriggerConsolidation triggers autonomous memory consolidation via HTTP API
+// TriggerConsolidation starts a consolidation job via HTTP API
 func (c *Client) TriggerConsolidation(ctx context.Context) (*models.ConsolidateResponse, error) {
 	url := fmt.Sprintf("%s/api/v1/journal/consolidate", c.baseURL)
 	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
@@ -154,7 +148,7 @@ func (c *Client) TriggerConsolidation(ctx context.Context) (*models.ConsolidateR
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusAccepted {
 		var errResp models.ErrorResponse
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
 			return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
@@ -170,6 +164,141 @@ func (c *Client) TriggerConsolidation(ctx context.Context) (*models.ConsolidateR
 	return &consolidateResp, nil
 }
 
+// GetJob retrieves a background job's status and progress via HTTP API
+func (c *Client) GetJob(ctx context.Context, id string) (*models.Job, error) {
+	url := fmt.Sprintf("%s/api/v1/jobs/%s", c.baseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+			return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("request failed: %s - %s", errResp.Error, errResp.Message)
+	}
+
+	var job models.Job
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// CancelJob requests cancellation of a background job via HTTP API
+func (c *Client) CancelJob(ctx context.Context, id string) (*models.Job, error) {
+	var job models.Job
+	if err := c.postJSON(ctx, fmt.Sprintf("/api/v1/jobs/%s/cancel", id), struct{}{}, http.StatusAccepted, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// DeleteMemory deletes a memory and its associations via HTTP API
+func (c *Client) DeleteMemory(ctx context.Context, id string) (*models.DeleteMemoryResponse, error) {
+	url := fmt.Sprintf("%s/api/v1/journal/%s", c.baseURL, id)
+	httpReq, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+			return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+		}
+		return nil, fmt.Errorf("request failed: %s - %s", errResp.Error, errResp.Message)
+	}
+
+	var deleteResp models.DeleteMemoryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&deleteResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &deleteResp, nil
+}
+
+// TriggerProcessorEvent queues a memory processor lifecycle event via HTTP API
+func (c *Client) TriggerProcessorEvent(ctx context.Context, eventType string, trigger string) (*models.ProcessorEventResponse, error) {
+	req := models.ProcessorEventRequest{
+		Type:    eventType,
+		Trigger: trigger,
+	}
+
+	var eventResp models.ProcessorEventResponse
+	if err := c.postJSON(ctx, "/api/v1/processor/events", req, http.StatusAccepted, &eventResp); err != nil {
+		return nil, err
+	}
+
+	return &eventResp, nil
+}
+
+// ReportContextUsage reports the agent's current context window usage via HTTP API
+func (c *Client) ReportContextUsage(ctx context.Context, tokens int) (*models.ContextUsageResponse, error) {
+	req := models.ContextUsageRequest{
+		Tokens: tokens,
+	}
+
+	var usageResp models.ContextUsageResponse
+	if err := c.postJSON(ctx, "/api/v1/processor/context-usage", req, http.StatusOK, &usageResp); err != nil {
+		return nil, err
+	}
+
+	return &usageResp, nil
+}
+
+// postJSON posts a JSON body and decodes the response when it has the expected status
+func (c *Client) postJSON(ctx context.Context, path string, body any, expected int, out any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s%s", c.baseURL, path)
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		var errResp models.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+			return fmt.Errorf("request failed with status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("request failed: %s - %s", errResp.Error, errResp.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
 // GetMemoryStats retrieves memory statistics via HTTP API
 func (c *Client) GetMemoryStats(ctx context.Context) (map[string]any, error) {
 	url := fmt.Sprintf("%s/api/v1/journal/stats", c.baseURL)
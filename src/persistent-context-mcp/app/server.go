@@ -60,7 +60,13 @@ func (s *Server) registerTools() {
 	s.registerGetMemoriesTool()
 	s.registerSearchMemoriesTool()
 	s.registerTriggerConsolidationTool()
+	s.registerGetJobTool()
+	s.registerCancelJobTool()
 	s.registerGetStatsTool()
+	s.registerForgetMemoryTool()
+	s.registerSessionStartTool()
+	s.registerSessionEndTool()
+	s.registerReportContextUsageTool()
 }
 
 
@@ -100,43 +106,38 @@ func (s *Server) registerGetStatsTool() {
 }
 
 
-// TriggerConsolidationResult represents the consolidation result
+// TriggerConsolidationResult represents the started consolidation job
 type TriggerConsolidationResult struct {
-	Success            bool `json:"success"`
-	Message            string `json:"message"`
-	GroupsFormed       int  `json:"groups_formed"`
-	GroupsConsolidated int  `json:"groups_consolidated"`
-	MemoriesProcessed  int  `json:"memories_processed"`
-	TotalMemories      int  `json:"total_memories"`
+	Success bool             `json:"success"`
+	JobID   string           `json:"job_id"`
+	Status  models.JobStatus `json:"status"`
+	Message string           `json:"message"`
 }
 
 // registerTriggerConsolidationTool adds the consolidation trigger tool
 func (s *Server) registerTriggerConsolidationTool() {
 	tool := &mcp.Tool{
 		Name:        "trigger_consolidation",
-		Description: "Trigger autonomous memory consolidation process",
+		Description: "Start a background memory consolidation job; poll it with get_job",
 	}
 
 	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[TriggerConsolidationResult], error) {
-		// Trigger autonomous consolidation via web service
+		// Start consolidation via web service
 		consolidateResp, err := s.httpClient.TriggerConsolidation(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to trigger consolidation: %w", err)
 		}
 
 		result := TriggerConsolidationResult{
-			Success:            true,
-			Message:            consolidateResp.Message,
-			GroupsFormed:       consolidateResp.GroupsFormed,
-			GroupsConsolidated: consolidateResp.GroupsConsolidated,
-			MemoriesProcessed:  consolidateResp.MemoriesProcessed,
-			TotalMemories:      consolidateResp.TotalMemories,
+			Success: true,
+			JobID:   consolidateResp.JobID,
+			Status:  consolidateResp.Status,
+			Message: consolidateResp.Message,
 		}
 
 		return &mcp.CallToolResultFor[TriggerConsolidationResult]{
 			Content: []mcp.Content{&mcp.TextContent{
-				Text: fmt.Sprintf("Consolidation completed: %d groups formed, %d groups consolidated, %d memories processed", 
-					consolidateResp.GroupsFormed, consolidateResp.GroupsConsolidated, consolidateResp.MemoriesProcessed),
+				Text: fmt.Sprintf("Consolidation job %s started; poll get_job for progress", consolidateResp.JobID),
 			}},
 			StructuredContent: result,
 		}, nil
@@ -145,6 +146,85 @@ func (s *Server) registerTriggerConsolidationTool() {
 	mcp.AddTool(s.mcpServer, tool, handler)
 }
 
+// JobParams identifies a background job
+type JobParams struct {
+	ID string `json:"id" mcp:"ID of the job returned by trigger_consolidation"`
+}
+
+// JobResult represents a background job's status and progress
+type JobResult struct {
+	Success bool        `json:"success"`
+	Job     *models.Job `json:"job"`
+}
+
+// registerGetJobTool adds the tool that polls a background job
+func (s *Server) registerGetJobTool() {
+	tool := &mcp.Tool{
+		Name:        "get_job",
+		Description: "Get the status, per-group progress, errors and created semantic memories of a background job",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[JobParams]) (*mcp.CallToolResultFor[JobResult], error) {
+		args := params.Arguments
+
+		if args.ID == "" {
+			return nil, fmt.Errorf("job id is required")
+		}
+
+		job, err := s.httpClient.GetJob(ctx, args.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job: %w", err)
+		}
+
+		return &mcp.CallToolResultFor[JobResult]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("Job %s is %s: %d of %d groups completed, %d consolidated, %d failed",
+					job.ID, job.Status, job.Progress.GroupsCompleted, job.Progress.GroupsFormed,
+					job.Progress.GroupsConsolidated, job.Progress.GroupsFailed),
+			}},
+			StructuredContent: JobResult{
+				Success: true,
+				Job:     job,
+			},
+		}, nil
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
+
+// registerCancelJobTool adds the tool that cancels a background job
+func (s *Server) registerCancelJobTool() {
+	tool := &mcp.Tool{
+		Name:        "cancel_job",
+		Description: "Cancel a running background job; groups already consolidated are kept",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[JobParams]) (*mcp.CallToolResultFor[JobResult], error) {
+		args := params.Arguments
+
+		if args.ID == "" {
+			return nil, fmt.Errorf("job id is required")
+		}
+
+		job, err := s.httpClient.CancelJob(ctx, args.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to cancel job: %w", err)
+		}
+
+		return &mcp.CallToolResultFor[JobResult]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("Job %s cancellation requested", job.ID),
+			}},
+			StructuredContent: JobResult{
+				Success: true,
+				Job:     job,
+			},
+		}, nil
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
+
 // CaptureMemoryParams represents the capture memory parameters
 type CaptureMemoryParams struct {
 	Source   string         `json:"source" mcp:"Source identifier for the memory"`
@@ -247,8 +327,11 @@ func (s *Server) registerGetMemoriesTool() {
 // SearchMemoriesParams represents the search memories parameters
 type SearchMemoriesParams struct {
 	Content    string  `json:"content" mcp:"Query text for similarity search"`
-	MemoryType *string `json:"memory_type,omitempty" mcp:"Type of memory to search (episodic, semantic, procedural)"`
-	Limit      *uint64 `json:"limit,omitempty" mcp:"Maximum number of results to return"`
+	MemoryType  *string            `json:"memory_type,omitempty" mcp:"Type of memory to search (episodic, semantic, procedural, metacognitive, or all)"`
+	Mode        *string            `json:"mode,omitempty" mcp:"Search mode: vector (default), keyword for exact identifiers, or hybrid"`
+	Limit       *uint64            `json:"limit,omitempty" mcp:"Maximum number of results to return"`
+	TypeWeights map[string]float64 `json:"type_weights,omitempty" mcp:"Per-type score weights when memory_type is all (0 excludes a type)"`
+	Explain     *bool              `json:"explain,omitempty" mcp:"Include a breakdown of each result's rank score"`
 }
 
 // SearchMemoriesResult represents the search memories result
@@ -262,7 +345,7 @@ type SearchMemoriesResult struct {
 func (s *Server) registerSearchMemoriesTool() {
 	tool := &mcp.Tool{
 		Name:        "search_memories",
-		Description: "Search memories by content similarity via the HTTP API",
+		Description: "Search memories by content similarity, keywords, or both via the HTTP API",
 	}
 
 	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[SearchMemoriesParams]) (*mcp.CallToolResultFor[SearchMemoriesResult], error) {
@@ -274,6 +357,12 @@ func (s *Server) registerSearchMemoriesTool() {
 			memoryType = models.MemoryType(*args.MemoryType)
 		}
 
+		// Determine search mode (empty lets web service default to vector)
+		mode := ""
+		if args.Mode != nil {
+			mode = *args.Mode
+		}
+
 		// Determine limit (let web service handle default if not specified)  
 		limit := uint64(0) // 0 signals web service to use default
 		if args.Limit != nil {
@@ -281,7 +370,14 @@ func (s *Server) registerSearchMemoriesTool() {
 		}
 
 		// Search memories via HTTP API
-		results, err := s.httpClient.QuerySimilarMemories(ctx, args.Content, memoryType, limit)
+		results, err := s.httpClient.SearchMemories(ctx, models.SearchMemoriesRequest{
+			Content:     args.Content,
+			MemoryType:  string(memoryType),
+			Mode:        mode,
+			Limit:       limit,
+			TypeWeights: args.TypeWeights,
+			Explain:     args.Explain != nil && *args.Explain,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("failed to search memories: %w", err)
 		}
@@ -304,3 +400,174 @@ func (s *Server) registerSearchMemoriesTool() {
 }
 
 
+
+// ForgetMemoryParams represents the forget memory parameters
+type ForgetMemoryParams struct {
+	ID string `json:"id" mcp:"ID of the memory to permanently delete"`
+}
+
+// ForgetMemoryResult represents the forget memory result
+type ForgetMemoryResult struct {
+	Success             bool   `json:"success"`
+	ID                  string `json:"id"`
+	Message             string `json:"message"`
+	AssociationsRemoved int    `json:"associations_removed"`
+	NeighborsUpdated    int    `json:"neighbors_updated"`
+}
+
+// registerForgetMemoryTool adds the memory deletion tool via HTTP API
+func (s *Server) registerForgetMemoryTool() {
+	tool := &mcp.Tool{
+		Name:        "forget_memory",
+		Description: "Permanently delete a memory and its associations, e.g. one that captured a secret or a wrong conclusion",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[ForgetMemoryParams]) (*mcp.CallToolResultFor[ForgetMemoryResult], error) {
+		args := params.Arguments
+
+		if args.ID == "" {
+			return nil, fmt.Errorf("memory id is required")
+		}
+
+		// Delete memory via HTTP API
+		deleteResp, err := s.httpClient.DeleteMemory(ctx, args.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to forget memory: %w", err)
+		}
+
+		result := ForgetMemoryResult{
+			Success:             true,
+			ID:                  deleteResp.ID,
+			Message:             deleteResp.Message,
+			AssociationsRemoved: deleteResp.AssociationsRemoved,
+			NeighborsUpdated:    deleteResp.NeighborsUpdated,
+		}
+
+		return &mcp.CallToolResultFor[ForgetMemoryResult]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("Memory %s forgotten: %d associations removed, %d neighbors updated",
+					deleteResp.ID, deleteResp.AssociationsRemoved, deleteResp.NeighborsUpdated),
+			}},
+			StructuredContent: result,
+		}, nil
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
+
+// ProcessorEventResult represents a queued processor event
+type ProcessorEventResult struct {
+	Success bool   `json:"success"`
+	Type    string `json:"type"`
+	Trigger string `json:"trigger"`
+	Message string `json:"message"`
+}
+
+// registerSessionStartTool adds the tool that signals a new agent session to the memory processor
+func (s *Server) registerSessionStartTool() {
+	tool := &mcp.Tool{
+		Name:        "session_start",
+		Description: "Signal that a new agent session has started so memories from the previous session can be consolidated",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[ProcessorEventResult], error) {
+		return s.triggerProcessorEvent(ctx, "context_init", "session_start")
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
+
+// registerSessionEndTool adds the tool that signals the end of an agent session to the memory processor
+func (s *Server) registerSessionEndTool() {
+	tool := &mcp.Tool{
+		Name:        "session_end",
+		Description: "Signal that the agent session is ending so recent memories are consolidated before context is lost",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[struct{}]) (*mcp.CallToolResultFor[ProcessorEventResult], error) {
+		return s.triggerProcessorEvent(ctx, "conversation_end", "session_end")
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
+
+// triggerProcessorEvent queues a processor event via HTTP API and builds the tool result
+func (s *Server) triggerProcessorEvent(ctx context.Context, eventType string, trigger string) (*mcp.CallToolResultFor[ProcessorEventResult], error) {
+	eventResp, err := s.httpClient.TriggerProcessorEvent(ctx, eventType, trigger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trigger %s event: %w", eventType, err)
+	}
+
+	result := ProcessorEventResult{
+		Success: true,
+		Type:    eventResp.Type,
+		Trigger: eventResp.Trigger,
+		Message: eventResp.Message,
+	}
+
+	return &mcp.CallToolResultFor[ProcessorEventResult]{
+		Content: []mcp.Content{&mcp.TextContent{
+			Text: fmt.Sprintf("%s event queued (trigger: %s)", eventResp.Type, eventResp.Trigger),
+		}},
+		StructuredContent: result,
+	}, nil
+}
+
+// ReportContextUsageParams represents the context usage parameters
+type ReportContextUsageParams struct {
+	Tokens int `json:"tokens" mcp:"Number of tokens currently used in the context window"`
+}
+
+// ReportContextUsageResult represents the context monitor state after a usage report
+type ReportContextUsageResult struct {
+	Success           bool    `json:"success"`
+	CurrentTokens     int     `json:"current_tokens"`
+	MaxTokens         int     `json:"max_tokens"`
+	Usage             float64 `json:"usage"`
+	ThresholdExceeded bool    `json:"threshold_exceeded"`
+	CanConsolidate    bool    `json:"can_consolidate"`
+}
+
+// registerReportContextUsageTool adds the tool that feeds real token counts to the context monitor
+func (s *Server) registerReportContextUsageTool() {
+	tool := &mcp.Tool{
+		Name:        "report_context_usage",
+		Description: "Report how many tokens of the context window are in use so consolidation respects the remaining budget",
+	}
+
+	handler := func(ctx context.Context, session *mcp.ServerSession, params *mcp.CallToolParamsFor[ReportContextUsageParams]) (*mcp.CallToolResultFor[ReportContextUsageResult], error) {
+		args := params.Arguments
+
+		if args.Tokens < 0 {
+			return nil, fmt.Errorf("tokens cannot be negative")
+		}
+
+		usageResp, err := s.httpClient.ReportContextUsage(ctx, args.Tokens)
+		if err != nil {
+			return nil, fmt.Errorf("failed to report context usage: %w", err)
+		}
+
+		result := ReportContextUsageResult{
+			Success:           true,
+			CurrentTokens:     usageResp.CurrentTokens,
+			MaxTokens:         usageResp.MaxTokens,
+			Usage:             usageResp.Usage,
+			ThresholdExceeded: usageResp.ThresholdExceeded,
+			CanConsolidate:    usageResp.CanConsolidate,
+		}
+
+		text := fmt.Sprintf("Context usage %d/%d tokens (%.0f%%)", usageResp.CurrentTokens, usageResp.MaxTokens, usageResp.Usage*100)
+		if usageResp.ThresholdExceeded {
+			text += "; usage threshold exceeded, consider calling session_end or trigger_consolidation"
+		}
+
+		return &mcp.CallToolResultFor[ReportContextUsageResult]{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: text,
+			}},
+			StructuredContent: result,
+		}, nil
+	}
+
+	mcp.AddTool(s.mcpServer, tool, handler)
+}
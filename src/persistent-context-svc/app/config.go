@@ -88,6 +88,38 @@ func (c *PersonaConfig) GetDefaults() map[string]any {
 	}
 }
 
+// JobsConfig holds background job configuration
+type JobsConfig struct {
+	StoragePath string `mapstructure:"storage_path"` // Directory job records are persisted to
+	MaxJobs     int    `mapstructure:"max_jobs"`     // Finished job records to keep
+}
+
+// LoadConfig loads configuration from viper
+func (c *JobsConfig) LoadConfig(v *viper.Viper) error {
+	return v.UnmarshalKey("jobs", c)
+}
+
+// ValidateConfig validates the configuration
+func (c *JobsConfig) ValidateConfig() error {
+	if c.StoragePath == "" {
+		return fmt.Errorf("jobs storage_path is required")
+	}
+	
+	if c.MaxJobs <= 0 {
+		return fmt.Errorf("max_jobs must be positive")
+	}
+	
+	return nil
+}
+
+// GetDefaults returns default configuration values
+func (c *JobsConfig) GetDefaults() map[string]any {
+	return map[string]any{
+		"jobs.storage_path": "./data/jobs/",
+		"jobs.max_jobs":     100,
+	}
+}
+
 // Config holds all web service configuration
 type Config struct {
 	HTTP     HTTPConfig             `mapstructure:"server"`
@@ -96,6 +128,7 @@ type Config struct {
 	LLM      config.LLMConfig       `mapstructure:"llm"`
 	Journal  config.JournalConfig   `mapstructure:"journal"`
 	Persona  PersonaConfig          `mapstructure:"persona"`
+	Jobs     JobsConfig             `mapstructure:"jobs"`
 	Memory   config.MemoryConfig    `mapstructure:"memory"`
 }
 
@@ -146,6 +179,7 @@ func (c *Config) loadPackageConfigs(v *viper.Viper) error {
 		&c.LLM,
 		&c.Journal,
 		&c.Persona,
+		&c.Jobs,
 		&c.Memory,
 	}
 	
@@ -168,6 +202,7 @@ func setDefaults(v *viper.Viper) {
 		&config.LLMConfig{},
 		&config.JournalConfig{},
 		&PersonaConfig{},
+		&JobsConfig{},
 		&config.MemoryConfig{},
 	}
 	
@@ -189,6 +224,7 @@ func (c *Config) Validate() error {
 		&c.LLM,
 		&c.Journal,
 		&c.Persona,
+		&c.Jobs,
 		&c.Memory,
 	}
 	
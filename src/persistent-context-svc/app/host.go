@@ -24,8 +24,11 @@ type Host struct {
 	// Service components
 	vectorDB        vectordb.VectorDB
 	llmClient       llm.LLM
+	llmCache        *llm.CachedLLM // Nil when the embedding cache is disabled
 	journal         journal.Journal
 	memoryProcessor *memory.Processor
+	scheduler       *ConsolidationScheduler
+	jobs            *JobManager
 	httpServer      *http.Server
 }
 
@@ -44,6 +47,14 @@ func (h *Host) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize host: %w", err)
 	}
 
+	// Start journal background workers before accepting captures
+	if err := h.journal.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start journal: %w", err)
+	}
+
+	// Jobs run until the host stops
+	h.jobs.Start(ctx)
+
 	// Start HTTP server
 	if err := h.startHTTPServer(); err != nil {
 		return fmt.Errorf("failed to start HTTP server: %w", err)
@@ -54,6 +65,11 @@ func (h *Host) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to start memory processor: %w", err)
 	}
 
+	// Start autonomous consolidation once the processor can accept events
+	if err := h.scheduler.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start consolidation scheduler: %w", err)
+	}
+
 	h.logger.Info("Host started successfully")
 	return nil
 }
@@ -62,6 +78,13 @@ func (h *Host) Start(ctx context.Context) error {
 func (h *Host) Stop(ctx context.Context) error {
 	h.logger.Info("Shutting down host...")
 
+	// Stop scheduling before the processor stops accepting events
+	if h.scheduler != nil {
+		if err := h.scheduler.Stop(ctx); err != nil {
+			h.logger.Error("Error stopping consolidation scheduler", "error", err)
+		}
+	}
+
 	// Stop memory processor
 	if h.memoryProcessor != nil {
 		h.memoryProcessor.Stop()
@@ -74,6 +97,27 @@ func (h *Host) Stop(ctx context.Context) error {
 		}
 	}
 
+	// Cancel running jobs so they record their final state
+	if h.jobs != nil {
+		if err := h.jobs.Stop(ctx); err != nil {
+			h.logger.Error("Error stopping jobs", "error", err)
+		}
+	}
+
+	// Drain queued journal work once no new captures can arrive
+	if h.journal != nil {
+		if err := h.journal.Stop(ctx); err != nil {
+			h.logger.Error("Error stopping journal", "error", err)
+		}
+	}
+
+	// Close the embedding cache after the last capture
+	if h.llmCache != nil {
+		if err := h.llmCache.Close(); err != nil {
+			h.logger.Error("Error closing embedding cache", "error", err)
+		}
+	}
+
 	h.logger.Info("Host stopped successfully")
 	return nil
 }
@@ -108,11 +152,15 @@ func (h *Host) initialize() error {
 		return fmt.Errorf("failed to initialize vector database: %w", err)
 	}
 
-	// Initialize LLM client
+	// Initialize LLM client; local embeddings must match the vector store's dimension
+	if h.config.LLM.Dimension == 0 {
+		h.config.LLM.Dimension = h.config.VectorDB.VectorDimension
+	}
 	h.llmClient, err = llm.NewLLM(&h.config.LLM)
 	if err != nil {
 		return fmt.Errorf("failed to create LLM client: %w", err)
 	}
+	h.llmCache, _ = h.llmClient.(*llm.CachedLLM)
 
 	// Initialize journal
 	journalDeps := &journal.Dependencies{
@@ -129,9 +177,23 @@ func (h *Host) initialize() error {
 
 	h.journal = journal.NewJournal(journalDeps)
 
+	// Restore the association graph persisted by previous runs
+	if err := h.journal.Initialize(context.Background()); err != nil {
+		return fmt.Errorf("failed to initialize journal: %w", err)
+	}
+
 	// Initialize memory processor
 	h.memoryProcessor = memory.NewProcessor(h.journal, h.llmClient, &h.config.Memory)
 
+	// Restore job records so clients can keep polling across restarts
+	h.jobs = NewJobManager(&h.config.Jobs)
+	if err := h.jobs.Load(); err != nil {
+		return fmt.Errorf("failed to load jobs: %w", err)
+	}
+
+	// Initialize consolidation scheduler
+	h.scheduler = NewConsolidationScheduler(h.memoryProcessor, h.journal, &h.config.Journal, &h.config.Memory)
+
 	return nil
 }
 
@@ -141,8 +203,12 @@ func (h *Host) startHTTPServer() error {
 	deps := &Dependencies{
 		VectorDBHealth: h.vectorDB,
 		LLMHealth:      h.llmClient,
+		LLMCache:       h.llmCache,
 		Journal:        h.journal,
 		VectorDB:       h.vectorDB,
+		Processor:      h.memoryProcessor,
+		Scheduler:      h.scheduler,
+		Jobs:           h.jobs,
 	}
 
 	// Create HTTP server using the server.go implementation
@@ -0,0 +1,338 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// ErrJobNotFound is returned when no job record has the requested ID
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that has already finished
+var ErrJobFinished = errors.New("job already finished")
+
+// ErrJobActive is returned when submitting a job while another of the same type is unfinished
+var ErrJobActive = errors.New("job already active")
+
+// JobFunc performs a job's work. It reports progress by calling update, which applies
+// the change under the manager's lock and persists the record.
+type JobFunc func(ctx context.Context, update func(change func(job *models.Job))) error
+
+// JobManager runs background jobs and persists their records as JSON files so
+// clients can keep polling across service restarts
+type JobManager struct {
+	storagePath string
+	maxJobs     int
+	mu          sync.RWMutex
+	jobs        map[string]*models.Job
+	cancels     map[string]context.CancelFunc
+	ctx         context.Context
+	stop        context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// NewJobManager creates a job manager that stores records under the configured path
+func NewJobManager(cfg *JobsConfig) *JobManager {
+	return &JobManager{
+		storagePath: cfg.StoragePath,
+		maxJobs:     cfg.MaxJobs,
+		jobs:        make(map[string]*models.Job),
+		cancels:     make(map[string]context.CancelFunc),
+	}
+}
+
+// Load restores persisted job records. Jobs that were pending or running when the
+// previous process exited are marked interrupted.
+func (jm *JobManager) Load() error {
+	if err := os.MkdirAll(jm.storagePath, 0o755); err != nil {
+		return fmt.Errorf("failed to create job storage: %w", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(jm.storagePath, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list job records: %w", err)
+	}
+
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	interrupted := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read job record %s: %w", file, err)
+		}
+
+		var job models.Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			slog.Warn("Skipping unreadable job record", "file", file, "error", err)
+			continue
+		}
+
+		if !job.Status.Finished() {
+			now := time.Now()
+			job.Status = models.JobInterrupted
+			job.Error = "service restarted before the job finished"
+			job.CompletedAt = &now
+			if err := jm.persist(&job); err != nil {
+				return err
+			}
+			interrupted++
+		}
+
+		jm.jobs[job.ID] = &job
+	}
+
+	slog.Info("Loaded job records", "jobs", len(jm.jobs), "interrupted", interrupted)
+	return nil
+}
+
+// Start binds job execution to ctx; running jobs are cancelled when it is done
+func (jm *JobManager) Start(ctx context.Context) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	jm.ctx, jm.stop = context.WithCancel(ctx)
+}
+
+// Stop cancels running jobs and waits for them to record their final state or for ctx to expire
+func (jm *JobManager) Stop(ctx context.Context) error {
+	jm.mu.Lock()
+	stop := jm.stop
+	jm.mu.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	stop()
+
+	done := make(chan struct{})
+	go func() {
+		jm.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("jobs did not stop: %w", ctx.Err())
+	}
+}
+
+// Submit records a new job of the given type and runs it in the background.
+// Only one unfinished job of each type may exist at a time.
+func (jm *JobManager) Submit(jobType string, run JobFunc) (*models.Job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	if jm.ctx == nil || jm.ctx.Err() != nil {
+		return nil, fmt.Errorf("job manager is not running")
+	}
+
+	for _, existing := range jm.jobs {
+		if existing.Type == jobType && !existing.Status.Finished() {
+			return nil, fmt.Errorf("%w: %s job %s", ErrJobActive, jobType, existing.ID)
+		}
+	}
+
+	job := &models.Job{
+		ID:          uuid.New().String(),
+		Type:        jobType,
+		Status:      models.JobPending,
+		SemanticIDs: []string{},
+		CreatedAt:   time.Now(),
+	}
+	if err := jm.persist(job); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(jm.ctx)
+	jm.jobs[job.ID] = job
+	jm.cancels[job.ID] = cancel
+	jm.prune()
+
+	jm.wg.Add(1)
+	go jm.execute(ctx, job.ID, run)
+
+	return cloneJob(job), nil
+}
+
+// execute runs a job and records its outcome
+func (jm *JobManager) execute(ctx context.Context, id string, run JobFunc) {
+	defer jm.wg.Done()
+
+	update := func(change func(job *models.Job)) {
+		jm.mu.Lock()
+		defer jm.mu.Unlock()
+
+		job, exists := jm.jobs[id]
+		if !exists {
+			slog.Warn("Dropping progress for unknown job", "job_id", id)
+			return
+		}
+		change(job)
+		if err := jm.persist(job); err != nil {
+			slog.Warn("Failed to persist job progress", "job_id", id, "error", err)
+		}
+	}
+
+	update(func(job *models.Job) {
+		now := time.Now()
+		job.StartedAt = &now
+		job.Status = models.JobRunning
+	})
+
+	err := run(ctx, update)
+
+	update(func(job *models.Job) {
+		now := time.Now()
+		job.CompletedAt = &now
+
+		switch {
+		case err == nil:
+			job.Status = models.JobCompleted
+		case ctx.Err() != nil && job.CancelRequested:
+			job.Status = models.JobCancelled
+			job.Error = "cancelled by request"
+		case ctx.Err() != nil:
+			job.Status = models.JobInterrupted
+			job.Error = "service stopped before the job finished"
+		default:
+			job.Status = models.JobFailed
+			job.Error = err.Error()
+		}
+
+		job.CancelRequested = false
+		jm.cancels[id]()
+		delete(jm.cancels, id)
+	})
+
+	slog.Info("Job finished", "job_id", id, "error", err)
+}
+
+// Get returns a snapshot of a job record
+func (jm *JobManager) Get(id string) (*models.Job, error) {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+
+	job, exists := jm.jobs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+	return cloneJob(job), nil
+}
+
+// List returns snapshots of every job record, newest first
+func (jm *JobManager) List() []*models.Job {
+	jm.mu.RLock()
+	defer jm.mu.RUnlock()
+
+	jobs := make([]*models.Job, 0, len(jm.jobs))
+	for _, job := range jm.jobs {
+		jobs = append(jobs, cloneJob(job))
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+	return jobs
+}
+
+// Cancel requests that an unfinished job stop. The job keeps its status, flagged as
+// cancelling, until its current step returns and it records its final state.
+func (jm *JobManager) Cancel(id string) (*models.Job, error) {
+	jm.mu.Lock()
+	defer jm.mu.Unlock()
+
+	job, exists := jm.jobs[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+	}
+
+	cancel, running := jm.cancels[id]
+	if job.Status.Finished() || !running {
+		return nil, fmt.Errorf("%w: %s is %s", ErrJobFinished, id, job.Status)
+	}
+
+	job.CancelRequested = true
+	job.Message = "cancellation requested"
+	if err := jm.persist(job); err != nil {
+		slog.Warn("Failed to persist job cancellation", "job_id", id, "error", err)
+	}
+	cancel()
+
+	return cloneJob(job), nil
+}
+
+// persist writes a job record atomically; callers must hold the lock
+func (jm *JobManager) persist(job *models.Job) error {
+	data, err := json.MarshalIndent(job, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode job %s: %w", job.ID, err)
+	}
+
+	path := jm.recordPath(job.ID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// prune removes the oldest finished records beyond maxJobs, never one whose goroutine is
+// still running; callers must hold the lock
+func (jm *JobManager) prune() {
+	var finished []*models.Job
+	for _, job := range jm.jobs {
+		if _, running := jm.cancels[job.ID]; job.Status.Finished() && !running {
+			finished = append(finished, job)
+		}
+	}
+
+	if len(finished) <= jm.maxJobs {
+		return
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.Before(finished[j].CreatedAt)
+	})
+	for _, job := range finished[:len(finished)-jm.maxJobs] {
+		delete(jm.jobs, job.ID)
+		if err := os.Remove(jm.recordPath(job.ID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to remove job record", "job_id", job.ID, "error", err)
+		}
+	}
+}
+
+// recordPath returns the file a job record is stored in
+func (jm *JobManager) recordPath(id string) string {
+	return filepath.Join(jm.storagePath, id+".json")
+}
+
+// cloneJob copies a job record so callers never share state with running jobs
+func cloneJob(job *models.Job) *models.Job {
+	clone := *job
+	clone.SemanticIDs = append([]string{}, job.SemanticIDs...)
+	clone.Groups = make([]*models.JobGroup, len(job.Groups))
+	for i, group := range job.Groups {
+		groupCopy := *group
+		groupCopy.MemoryIDs = append([]string{}, group.MemoryIDs...)
+		clone.Groups[i] = &groupCopy
+	}
+	return &clone
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// newTestJobManager returns a started job manager keeping one finished record
+func newTestJobManager(t *testing.T) *JobManager {
+	t.Helper()
+
+	jm := NewJobManager(&JobsConfig{StoragePath: t.TempDir(), MaxJobs: 1})
+	if err := jm.Load(); err != nil {
+		t.Fatalf("failed to load jobs: %v", err)
+	}
+	jm.Start(context.Background())
+	t.Cleanup(func() {
+		if err := jm.Stop(context.Background()); err != nil {
+			t.Errorf("failed to stop jobs: %v", err)
+		}
+	})
+	return jm
+}
+
+// waitForStatus polls a job until it reaches status or the test times out
+func waitForStatus(t *testing.T, jm *JobManager, id string, status models.JobStatus) *models.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		job, err := jm.Get(id)
+		if err != nil {
+			t.Fatalf("failed to get job: %v", err)
+		}
+		if job.Status == status {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s never reached %s", id, status)
+	return nil
+}
+
+func TestCancelKeepsJobRunningUntilItStops(t *testing.T) {
+	jm := newTestJobManager(t)
+
+	// The job ignores cancellation until release is closed, like a job inside an LLM call
+	release := make(chan struct{})
+	finish := sync.OnceFunc(func() { close(release) })
+	t.Cleanup(finish) // Runs before the manager stops, even when the test fails early
+	job, err := jm.Submit("slow", func(ctx context.Context, update func(change func(job *models.Job))) error {
+		<-release
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("failed to submit job: %v", err)
+	}
+	waitForStatus(t, jm, job.ID, models.JobRunning)
+
+	cancelled, err := jm.Cancel(job.ID)
+	if err != nil {
+		t.Fatalf("failed to cancel job: %v", err)
+	}
+	if cancelled.Status != models.JobRunning || !cancelled.CancelRequested {
+		t.Errorf("cancelled job is %s with cancel_requested=%v, want running with cancel_requested", cancelled.Status, cancelled.CancelRequested)
+	}
+
+	if _, err := jm.Submit("slow", func(ctx context.Context, update func(change func(job *models.Job))) error {
+		return nil
+	}); !errors.Is(err, ErrJobActive) {
+		t.Errorf("second job while cancelling returned %v, want %v", err, ErrJobActive)
+	}
+
+	// Finishing other jobs prunes finished records, which must not include the running one
+	for range 3 {
+		other, err := jm.Submit("quick", func(ctx context.Context, update func(change func(job *models.Job))) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to submit job: %v", err)
+		}
+		waitForStatus(t, jm, other.ID, models.JobCompleted)
+	}
+	if _, err := jm.Get(job.ID); err != nil {
+		t.Fatalf("running job was pruned: %v", err)
+	}
+
+	finish()
+	final := waitForStatus(t, jm, job.ID, models.JobCancelled)
+	if final.CancelRequested {
+		t.Error("finished job still reports cancel_requested")
+	}
+}
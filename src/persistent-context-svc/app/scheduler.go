@@ -0,0 +1,202 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/journal"
+	"github.com/JaimeStill/persistent-context/pkg/memory"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// ConsolidationScheduler drives autonomous consolidation. It fires a NewContext event
+// into the memory processor every ConsolidationInterval (with jitter) and a
+// ThresholdReached event whenever MemoryCountThreshold episodic memories are waiting
+// to be consolidated. Each event is awaited before the next is considered,
+// so scheduled runs never overlap.
+type ConsolidationScheduler struct {
+	processor     *memory.Processor
+	journal       journal.Journal
+	interval      time.Duration
+	jitter        float64
+	checkInterval time.Duration
+	threshold     uint32
+
+	mu          sync.Mutex
+	cancel      context.CancelFunc
+	done        chan struct{}
+	nextRun     time.Time
+	lastRun     time.Time
+	lastTrigger string
+	lastErr     error
+	runs        int
+	backoff     bool // The last run failed; threshold checks wait for the next interval run
+}
+
+// NewConsolidationScheduler creates a scheduler from the journal and memory configuration
+func NewConsolidationScheduler(processor *memory.Processor, journal journal.Journal, journalConfig *config.JournalConfig, memoryConfig *config.MemoryConfig) *ConsolidationScheduler {
+	return &ConsolidationScheduler{
+		processor:     processor,
+		journal:       journal,
+		interval:      journalConfig.ConsolidationInterval,
+		jitter:        journalConfig.ConsolidationJitter,
+		checkInterval: journalConfig.ConsolidationCheck,
+		threshold:     memoryConfig.MemoryCountThreshold,
+	}
+}
+
+// Start launches the scheduling loop; it stops when ctx is cancelled or Stop is called
+func (cs *ConsolidationScheduler) Start(ctx context.Context) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.cancel != nil {
+		return nil
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	cs.cancel = cancel
+	cs.done = make(chan struct{})
+	cs.nextRun = time.Now().Add(cs.nextDelay())
+
+	go cs.loop(loopCtx, cs.done)
+
+	slog.Info("Consolidation scheduler started",
+		"interval", cs.interval,
+		"jitter", cs.jitter,
+		"check_interval", cs.checkInterval,
+		"threshold", cs.threshold,
+		"next_run", cs.nextRun)
+	return nil
+}
+
+// Stop cancels the scheduling loop and waits for it to exit or for ctx to expire
+func (cs *ConsolidationScheduler) Stop(ctx context.Context) error {
+	cs.mu.Lock()
+	cancel, done := cs.cancel, cs.done
+	cs.cancel, cs.done = nil, nil
+	cs.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("consolidation scheduler did not stop: %w", ctx.Err())
+	}
+}
+
+// loop checks the threshold on every tick and runs the interval consolidation when it is due
+func (cs *ConsolidationScheduler) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(cs.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		cs.mu.Lock()
+		wait := time.Until(cs.nextRun)
+		cs.mu.Unlock()
+
+		timer := time.NewTimer(max(wait, 0))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			cs.run(ctx, memory.NewContext, "scheduled_interval", nil)
+		case <-ticker.C:
+			timer.Stop()
+			if cs.backingOff() {
+				continue
+			}
+			if memories := cs.unconsolidated(ctx); memories != nil {
+				cs.run(ctx, memory.ThresholdReached, "episode_threshold", memories)
+			}
+		}
+	}
+}
+
+// unconsolidated returns the episodes not yet consolidated once they reach the threshold.
+// Consolidation state is stored on the episodes, so the count survives restarts.
+func (cs *ConsolidationScheduler) unconsolidated(ctx context.Context) []*models.MemoryEntry {
+	memories, err := cs.journal.ListMemories(ctx, cs.threshold, journal.UnconsolidatedFilter())
+	if err != nil {
+		slog.Warn("Failed to count unconsolidated episodes", "error", err)
+		return nil
+	}
+
+	if uint32(len(memories)) < cs.threshold {
+		return nil
+	}
+	return memories
+}
+
+// run fires an event, waits for the processor to handle it and schedules the next interval run
+func (cs *ConsolidationScheduler) run(ctx context.Context, eventType memory.EventType, trigger string, memories []*models.MemoryEntry) {
+	err := cs.processor.TriggerEventAndWait(ctx, eventType, trigger, memories)
+	if err != nil && ctx.Err() == nil {
+		slog.Error("Scheduled consolidation failed", "trigger", trigger, "error", err)
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.lastRun = time.Now()
+	cs.lastTrigger = trigger
+	cs.lastErr = err
+	cs.backoff = err != nil
+	cs.runs++
+	cs.nextRun = cs.lastRun.Add(cs.nextDelay())
+}
+
+// backingOff reports whether threshold checks are paused after a failed run, so an
+// unreachable model isn't retried on every check while the episodes stay unconsolidated
+func (cs *ConsolidationScheduler) backingOff() bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	return cs.backoff
+}
+
+// nextDelay returns the interval spread randomly by up to ±jitter of its length
+func (cs *ConsolidationScheduler) nextDelay() time.Duration {
+	spread := (rand.Float64()*2 - 1) * cs.jitter
+	return time.Duration(float64(cs.interval) * (1 + spread))
+}
+
+// Stats reports the schedule and the outcome of the last run
+func (cs *ConsolidationScheduler) Stats() map[string]any {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	stats := map[string]any{
+		"running":   cs.cancel != nil,
+		"interval":  cs.interval.String(),
+		"jitter":    cs.jitter,
+		"threshold": cs.threshold,
+		"runs":      cs.runs,
+	}
+	if cs.cancel != nil {
+		stats["next_run"] = cs.nextRun
+	}
+	if !cs.lastRun.IsZero() {
+		stats["last_run"] = cs.lastRun
+		stats["last_trigger"] = cs.lastTrigger
+	}
+	if cs.lastErr != nil {
+		stats["last_error"] = cs.lastErr.Error()
+		stats["threshold_paused"] = cs.backoff
+	}
+	return stats
+}
@@ -2,13 +2,19 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/JaimeStill/persistent-context/pkg/journal"
+	"github.com/JaimeStill/persistent-context/pkg/memory"
 	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/JaimeStill/persistent-context/pkg/vectordb"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // Server represents the HTTP server
@@ -63,10 +69,31 @@ func (s *Server) registerRoutes() {
 	{
 		// Journal endpoints
 		api.POST("/journal", s.handleCaptureMemory)
+		api.POST("/journal/batch", s.handleCaptureBatch)
 		api.GET("/journal", s.handleGetMemories)
 		api.POST("/journal/search", s.handleSearchMemories)
 		api.POST("/journal/consolidate", s.handleConsolidation)
 		api.GET("/journal/stats", s.handleGetMemoryStats)
+		api.GET("/journal/retention", s.handleRetentionPreview)
+		api.GET("/journal/:id", s.handleGetMemory)
+		api.PATCH("/journal/:id", s.handleUpdateMemory)
+		api.DELETE("/journal/:id", s.handleDeleteMemory)
+		api.GET("/journal/:id/versions", s.handleListMemoryVersions)
+		api.POST("/journal/:id/versions/:version/restore", s.handleRestoreMemoryVersion)
+		api.GET("/journal/:id/lineage", s.handleGetLineage)
+		api.POST("/journal/:id/rollback", s.handleRollbackConsolidation)
+
+		// Job endpoints
+		api.GET("/jobs", s.handleListJobs)
+		api.GET("/jobs/:id", s.handleGetJob)
+		api.POST("/jobs/:id/cancel", s.handleCancelJob)
+
+		// LLM endpoints
+		api.GET("/llm/cache", s.handleGetCacheStats)
+
+		// Memory processor endpoints
+		api.POST("/processor/events", s.handleProcessorEvent)
+		api.POST("/processor/context-usage", s.handleContextUsage)
 	}
 }
 
@@ -183,6 +210,36 @@ func (s *Server) handleCaptureMemory(c *gin.Context) {
 	})
 }
 
+// handleCaptureBatch handles POST /api/v1/journal/batch
+func (s *Server) handleCaptureBatch(c *gin.Context) {
+	var req models.CaptureBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	entries, err := s.deps.Journal.CaptureBatch(ctx, req.Memories)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.CaptureBatchErrorResponse{
+			Error:   "capture_failed",
+			Message: fmt.Sprintf("%v (%d of %d memories captured before the failure)", err, len(entries), len(req.Memories)),
+			IDs:     memoryIDs(entries),
+			Count:   len(entries),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.CaptureBatchResponse{
+		IDs:     memoryIDs(entries),
+		Count:   len(entries),
+		Message: "Memories captured successfully",
+	})
+}
+
 // handleGetMemories handles GET /api/v1/journal
 func (s *Server) handleGetMemories(c *gin.Context) {
 	var req models.GetMemoriesRequest
@@ -194,6 +251,15 @@ func (s *Server) handleGetMemories(c *gin.Context) {
 		return
 	}
 
+	filter, err := req.BuildFilter(time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Apply default limit if not specified
 	limit := req.Limit
 	if limit == 0 {
@@ -201,7 +267,7 @@ func (s *Server) handleGetMemories(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	memories, err := s.deps.Journal.GetMemories(ctx, limit)
+	memories, err := s.deps.Journal.ListMemories(ctx, limit, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "retrieval_failed",
@@ -235,14 +301,50 @@ func (s *Server) handleSearchMemories(c *gin.Context) {
 		memType = models.MemoryType(req.MemoryType)
 	}
 
+	mode, err := models.ParseSearchMode(req.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := req.Filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	// Apply default limit if not specified
 	limit := req.Limit
 	if limit == 0 {
 		limit = 10 // Default limit
 	}
 
+	typeWeights := make(map[models.MemoryType]float64, len(req.TypeWeights))
+	for memoryType, weight := range req.TypeWeights {
+		if weight < 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_request",
+				Message: fmt.Sprintf("type weight for %s cannot be negative", memoryType),
+			})
+			return
+		}
+		typeWeights[models.MemoryType(memoryType)] = weight
+	}
+
 	ctx := c.Request.Context()
-	memories, err := s.deps.Journal.QuerySimilarMemories(ctx, req.Content, memType, limit)
+	memories, err := s.deps.Journal.SearchMemories(ctx, req.Content, journal.SearchOptions{
+		MemoryType:  memType,
+		Mode:        mode,
+		Limit:       limit,
+		TypeWeights: typeWeights,
+		Explain:     req.Explain,
+		Filter:      req.Filter,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error:   "search_failed",
@@ -254,53 +356,598 @@ func (s *Server) handleSearchMemories(c *gin.Context) {
 	c.JSON(http.StatusOK, models.SearchMemoriesResponse{
 		Memories: memories,
 		Query:    req.Content,
+		Mode:     mode,
 		Count:    len(memories),
 		Limit:    limit,
 	})
 }
 
-// handleConsolidation handles POST /api/v1/journal/consolidate
+// consolidationJobType identifies consolidation runs in the job manager
+const consolidationJobType = "consolidation"
+
+// handleConsolidation handles POST /api/v1/journal/consolidate by starting a background
+// consolidation job; progress is polled through GET /api/v1/jobs/:id
 func (s *Server) handleConsolidation(c *gin.Context) {
-	ctx := c.Request.Context()
+	// The body is optional and only tunes batching
+	var req models.ConsolidateRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
 
-	// Get recent episodic memories for intelligent consolidation
-	memories, err := s.deps.Journal.GetMemories(ctx, 100)
+	opts := journal.ConsolidateOptions{BatchSize: req.BatchSize}
+	job, err := s.deps.Jobs.Submit(consolidationJobType, func(ctx context.Context, update func(change func(job *models.Job))) error {
+		// Hold off processor events so they can't consolidate the episodes this job selects
+		return s.deps.Processor.Exclusive(ctx, func(ctx context.Context) error {
+			return s.consolidate(ctx, update, opts)
+		})
+	})
+	if errors.Is(err, ErrJobActive) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "job_active",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "retrieval_failed",
-			Message: fmt.Sprintf("Failed to get memories for consolidation: %v", err),
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error:   "job_failed",
+			Message: err.Error(),
 		})
 		return
 	}
 
+	c.Header("Location", fmt.Sprintf("/api/v1/jobs/%s", job.ID))
+	c.JSON(http.StatusAccepted, models.ConsolidateResponse{
+		JobID:   job.ID,
+		Status:  job.Status,
+		Message: "Consolidation started",
+	})
+}
+
+// consolidate groups recent episodic memories by association and consolidates each
+// group with more than one memory, recording progress on the job
+func (s *Server) consolidate(ctx context.Context, update func(change func(job *models.Job)), opts journal.ConsolidateOptions) error {
+	// Get recent episodic memories that have not been consolidated yet
+	memories, err := s.deps.Journal.ListMemories(ctx, 100, journal.UnconsolidatedFilter())
+	if err != nil {
+		return fmt.Errorf("failed to get memories for consolidation: %w", err)
+	}
+
 	// Group memories by associations for intelligent consolidation
 	groupedMemories := s.groupMemoriesByAssociations(memories)
-	
-	totalProcessed := 0
-	groupsConsolidated := 0
-	
-	for _, group := range groupedMemories {
+
+	update(func(job *models.Job) {
+		job.Progress.TotalMemories = len(memories)
+		job.Progress.GroupsFormed = len(groupedMemories)
+		job.Groups = make([]*models.JobGroup, len(groupedMemories))
+		for i, group := range groupedMemories {
+			job.Groups[i] = &models.JobGroup{
+				MemoryIDs: memoryIDs(group),
+				Status:    models.JobPending,
+			}
+		}
+	})
+
+	for i, group := range groupedMemories {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// Only consolidate groups with multiple memories
-		if len(group) > 1 {
-			err = s.deps.Journal.ConsolidateMemories(ctx, group)
+		if len(group) < 2 {
+			update(func(job *models.Job) {
+				job.Groups[i].Status = models.JobCompleted
+				job.Groups[i].Skipped = true
+				job.Progress.GroupsCompleted++
+			})
+			continue
+		}
+
+		update(func(job *models.Job) {
+			job.Groups[i].Status = models.JobRunning
+			job.Message = fmt.Sprintf("consolidating group %d of %d", i+1, len(groupedMemories))
+		})
+
+		semantic, err := s.deps.Journal.ConsolidateMemories(ctx, group, opts)
+		if err != nil && ctx.Err() != nil {
+			update(func(job *models.Job) {
+				job.Groups[i].Status = models.JobCancelled
+			})
+			return ctx.Err()
+		}
+
+		update(func(job *models.Job) {
+			job.Progress.GroupsCompleted++
 			if err != nil {
 				slog.Warn("Failed to consolidate memory group", "error", err, "group_size", len(group))
-				continue
+				job.Groups[i].Status = models.JobFailed
+				job.Groups[i].Error = err.Error()
+				job.Progress.GroupsFailed++
+				return
 			}
-			totalProcessed += len(group)
-			groupsConsolidated++
+
+			job.Groups[i].Status = models.JobCompleted
+			job.Groups[i].SemanticID = semantic.ID
+			job.SemanticIDs = append(job.SemanticIDs, semantic.ID)
+			job.Progress.GroupsConsolidated++
+			job.Progress.MemoriesProcessed += len(group)
+		})
+	}
+
+	update(func(job *models.Job) {
+		job.Message = fmt.Sprintf("consolidated %d of %d groups", job.Progress.GroupsConsolidated, job.Progress.GroupsFormed)
+	})
+	return nil
+}
+
+// memoryIDs returns the IDs of the given memories
+func memoryIDs(memories []*models.MemoryEntry) []string {
+	ids := make([]string, len(memories))
+	for i, memory := range memories {
+		ids[i] = memory.ID
+	}
+	return ids
+}
+
+// handleGetCacheStats handles GET /api/v1/llm/cache
+func (s *Server) handleGetCacheStats(c *gin.Context) {
+	if s.deps.LLMCache == nil {
+		c.JSON(http.StatusOK, models.CacheStats{Enabled: false})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.deps.LLMCache.Stats())
+}
+
+// handleListJobs handles GET /api/v1/jobs
+func (s *Server) handleListJobs(c *gin.Context) {
+	jobs := s.deps.Jobs.List()
+
+	c.JSON(http.StatusOK, models.ListJobsResponse{
+		Jobs:  jobs,
+		Count: len(jobs),
+	})
+}
+
+// handleGetJob handles GET /api/v1/jobs/:id
+func (s *Server) handleGetJob(c *gin.Context) {
+	job, err := s.deps.Jobs.Get(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// handleCancelJob handles POST /api/v1/jobs/:id/cancel
+func (s *Server) handleCancelJob(c *gin.Context) {
+	job, err := s.deps.Jobs.Cancel(c.Param("id"))
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, ErrJobFinished):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   "job_finished",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "cancel_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// handleGetMemory handles GET /api/v1/journal/:id
+func (s *Server) handleGetMemory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	var req models.GetMemoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	entry, err := s.deps.Journal.GetMemoryByID(ctx, id)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "retrieval_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := models.GetMemoryResponse{MemoryEntry: entry}
+
+	if req.IncludeAssociations {
+		if response.Associations, err = s.deps.Journal.GetMemoryAssociations(ctx, id); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "retrieval_failed",
+				Message: err.Error(),
+			})
+			return
 		}
 	}
 
-	c.JSON(http.StatusOK, models.ConsolidateResponse{
-		Message:            "Intelligent consolidation completed",
-		GroupsFormed:       len(groupedMemories),
-		GroupsConsolidated: groupsConsolidated,
-		MemoriesProcessed:  totalProcessed,
-		TotalMemories:      len(memories),
+	if req.IncludeSources {
+		if response.Sources, err = s.deps.Journal.GetSourceMemories(ctx, entry); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "retrieval_failed",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// handleProcessorEvent handles POST /api/v1/processor/events, queueing a lifecycle event for the memory processor
+func (s *Server) handleProcessorEvent(c *gin.Context) {
+	var req models.ProcessorEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	eventType, err := memory.ParseEventType(req.Type)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if req.Trigger == "" {
+		req.Trigger = "api"
+	}
+
+	// The other events load their own memories; ThresholdReached consolidates the ones it is given
+	var memories []*models.MemoryEntry
+	if eventType == memory.ThresholdReached {
+		ctx := c.Request.Context()
+		memories, err = s.deps.Journal.ListMemories(ctx, s.config.Memory.MemoryCountThreshold, journal.UnconsolidatedFilter())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   "retrieval_failed",
+				Message: fmt.Sprintf("Failed to get memories for consolidation: %v", err),
+			})
+			return
+		}
+	}
+
+	if err := s.deps.Processor.TriggerEvent(eventType, req.Trigger, memories); err != nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
+			Error:   "processor_unavailable",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.ProcessorEventResponse{
+		Type:    eventType.String(),
+		Trigger: req.Trigger,
+		Message: "Event queued for processing",
+	})
+}
+
+// handleContextUsage handles POST /api/v1/processor/context-usage, feeding the context monitor a token count
+func (s *Server) handleContextUsage(c *gin.Context) {
+	var req models.ContextUsageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.deps.Processor.UpdateContextUsage(req.Tokens)
+	state := s.deps.Processor.ContextState()
+
+	usage := 0.0
+	if state.WindowSize > 0 {
+		usage = float64(state.CurrentUsage) / float64(state.WindowSize)
+	}
+
+	c.JSON(http.StatusOK, models.ContextUsageResponse{
+		CurrentTokens:     state.CurrentUsage,
+		MaxTokens:         state.WindowSize,
+		Usage:             usage,
+		ThresholdExceeded: usage >= s.config.Memory.ContextUsageThreshold,
+		CanConsolidate:    state.CanProceed,
+	})
+}
+
+// handleRetentionPreview handles GET /api/v1/journal/retention, reporting what a retention run would forget without deleting anything
+func (s *Server) handleRetentionPreview(c *gin.Context) {
+	ctx := c.Request.Context()
+	report, err := s.deps.Journal.ApplyRetention(ctx, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "retention_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleUpdateMemory handles PATCH /api/v1/journal/:id
+func (s *Server) handleUpdateMemory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	var req models.UpdateMemoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	entry, err := s.deps.Journal.UpdateMemory(ctx, id, models.MemoryUpdate{
+		Content:  req.Content,
+		Metadata: req.Metadata,
+		Editor:   req.Editor,
+	})
+	if err != nil {
+		s.respondRevisionError(c, "update_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UpdateMemoryResponse{
+		Memory:  entry,
+		Version: journal.VersionNumber(entry),
+		Message: "Memory updated successfully",
+	})
+}
+
+// handleListMemoryVersions handles GET /api/v1/journal/:id/versions
+func (s *Server) handleListMemoryVersions(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	versions, err := s.deps.Journal.ListMemoryVersions(ctx, id)
+	if err != nil {
+		s.respondRevisionError(c, "retrieval_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ListVersionsResponse{
+		MemoryID: id,
+		Versions: versions,
+		Count:    len(versions),
+	})
+}
+
+// handleRestoreMemoryVersion handles POST /api/v1/journal/:id/versions/:version/restore
+func (s *Server) handleRestoreMemoryVersion(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid version: %s", c.Param("version")),
+		})
+		return
+	}
+
+	// The body is optional and only names the editor
+	var req models.RestoreVersionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	entry, err := s.deps.Journal.RestoreMemoryVersion(ctx, id, version, req.Editor)
+	if err != nil {
+		s.respondRevisionError(c, "restore_failed", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.UpdateMemoryResponse{
+		Memory:  entry,
+		Version: journal.VersionNumber(entry),
+		Message: fmt.Sprintf("Restored version %d", version),
 	})
 }
 
+// respondRevisionError maps journal edit errors to HTTP status codes
+func (s *Server) respondRevisionError(c *gin.Context, code string, err error) {
+	switch {
+	case errors.Is(err, vectordb.ErrMemoryNotFound), errors.Is(err, journal.ErrVersionNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, journal.ErrInvalidUpdate):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   code,
+			Message: err.Error(),
+		})
+	}
+}
+
+// handleDeleteMemory handles DELETE /api/v1/journal/:id
+func (s *Server) handleDeleteMemory(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := s.deps.Journal.DeleteMemory(ctx, id)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "delete_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DeleteMemoryResponse{
+		ID:                  id,
+		Message:             "Memory deleted successfully",
+		AssociationsRemoved: result.AssociationsRemoved,
+		NeighborsUpdated:    result.NeighborsUpdated,
+	})
+}
+
+// handleGetLineage handles GET /api/v1/journal/:id/lineage
+func (s *Server) handleGetLineage(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	lineage, err := s.deps.Journal.GetLineage(ctx, id)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "retrieval_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, lineage)
+}
+
+// handleRollbackConsolidation handles POST /api/v1/journal/:id/rollback
+func (s *Server) handleRollbackConsolidation(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "invalid_request",
+			Message: fmt.Sprintf("invalid memory id: %s", id),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	result, err := s.deps.Journal.RollbackConsolidation(ctx, id)
+	switch {
+	case errors.Is(err, vectordb.ErrMemoryNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   "not_found",
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, journal.ErrNotConsolidated):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   "not_consolidated",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   "rollback_failed",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // handleGetMemoryStats handles GET /api/v1/journal/stats
 func (s *Server) handleGetMemoryStats(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -313,6 +960,10 @@ func (s *Server) handleGetMemoryStats(c *gin.Context) {
 		return
 	}
 
+	if s.deps.Scheduler != nil {
+		stats["consolidation_scheduler"] = s.deps.Scheduler.Stats()
+	}
+
 	c.JSON(http.StatusOK, models.StatsResponse{
 		Stats: stats,
 	})
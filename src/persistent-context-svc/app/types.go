@@ -4,6 +4,8 @@ import (
 	"context"
 	
 	"github.com/JaimeStill/persistent-context/pkg/journal"
+	"github.com/JaimeStill/persistent-context/pkg/llm"
+	"github.com/JaimeStill/persistent-context/pkg/memory"
 	"github.com/JaimeStill/persistent-context/pkg/vectordb"
 )
 
@@ -16,6 +18,10 @@ type HealthChecker interface {
 type Dependencies struct {
 	VectorDBHealth HealthChecker
 	LLMHealth      HealthChecker
+	LLMCache       *llm.CachedLLM
 	Journal        journal.Journal
 	VectorDB       vectordb.VectorDB
+	Processor      *memory.Processor
+	Scheduler      *ConsolidationScheduler
+	Jobs           *JobManager
 }
\ No newline at end of file
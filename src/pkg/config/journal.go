@@ -9,11 +9,22 @@ import (
 
 // JournalConfig holds journal processing configuration
 type JournalConfig struct {
-	BatchSize             uint32        `mapstructure:"batch_size"`             // Batch size for processing
-	RetentionDays         int           `mapstructure:"retention_days"`         // Days to retain memories
-	ConsolidationInterval time.Duration `mapstructure:"consolidation_interval"` // How often to consolidate
-	MaxMemorySize         uint64        `mapstructure:"max_memory_size"`        // Max memories to keep
-	StrengthThreshold     float32       `mapstructure:"strength_threshold"`     // Minimum strength to keep
+	BatchSize                uint32        `mapstructure:"batch_size"`                 // Batch size for processing
+	RetentionDays            int           `mapstructure:"retention_days"`             // Days to retain episodic memories (0 keeps them indefinitely)
+	RetentionInterval        time.Duration `mapstructure:"retention_interval"`         // How often to enforce retention (0 disables scheduled runs)
+	ConsolidationInterval    time.Duration `mapstructure:"consolidation_interval"`     // How often to consolidate
+	ConsolidationJitter      float64       `mapstructure:"consolidation_jitter"`       // Random spread applied to the interval (0.0-1.0)
+	ConsolidationCheck       time.Duration `mapstructure:"consolidation_check"`        // How often to count unconsolidated episodes
+	ConsolidatedDecay        float64       `mapstructure:"consolidated_decay"`         // Strength multiplier applied to consolidated episodes (1.0 keeps their strength)
+	ConsolidationBatchTokens int           `mapstructure:"consolidation_batch_tokens"` // Token budget per consolidation prompt, capped by the memory context window (0 uses the window)
+	ExtractiveFallback       bool          `mapstructure:"extractive_fallback"`        // Store an extractive summary when the model fails (its sources stay unconsolidated)
+	ChunkTokens              int           `mapstructure:"chunk_tokens"`               // Captured content longer than this is embedded in chunks (0 embeds it whole)
+	ChunkOverlapTokens       int           `mapstructure:"chunk_overlap_tokens"`       // Content repeated from the end of one chunk at the start of the next
+	MaxMemorySize            uint64        `mapstructure:"max_memory_size"`            // Max memories to keep
+	StrengthThreshold        float32       `mapstructure:"strength_threshold"`         // Decayed strength below which episodic memories are dropped (0 disables)
+	StrengthMinAgeDays       int           `mapstructure:"strength_min_age_days"`      // Days an episodic memory is kept before the strength threshold applies
+	AssociationWorkers       int           `mapstructure:"association_workers"`        // Concurrent association analysis workers
+	AssociationQueueSize     int           `mapstructure:"association_queue_size"`     // Pending analyses before captures block
 }
 
 // LoadConfig loads configuration from viper
@@ -31,10 +42,38 @@ func (c *JournalConfig) ValidateConfig() error {
 		return fmt.Errorf("retention days cannot be negative")
 	}
 	
+	if c.RetentionInterval < 0 {
+		return fmt.Errorf("retention interval cannot be negative")
+	}
+	
 	if c.ConsolidationInterval <= 0 {
 		return fmt.Errorf("consolidation interval must be positive")
 	}
 	
+	if c.ConsolidationJitter < 0 || c.ConsolidationJitter > 1 {
+		return fmt.Errorf("consolidation jitter must be between 0 and 1")
+	}
+	
+	if c.ConsolidationCheck <= 0 {
+		return fmt.Errorf("consolidation check must be positive")
+	}
+	
+	if c.ConsolidatedDecay < 0 || c.ConsolidatedDecay > 1 {
+		return fmt.Errorf("consolidated decay must be between 0 and 1")
+	}
+	
+	if c.ConsolidationBatchTokens < 0 {
+		return fmt.Errorf("consolidation batch tokens cannot be negative")
+	}
+	
+	if c.ChunkTokens < 0 {
+		return fmt.Errorf("chunk tokens cannot be negative")
+	}
+	
+	if c.ChunkOverlapTokens < 0 || (c.ChunkTokens > 0 && c.ChunkOverlapTokens >= c.ChunkTokens) {
+		return fmt.Errorf("chunk overlap tokens must be non-negative and smaller than chunk tokens")
+	}
+	
 	if c.MaxMemorySize == 0 {
 		return fmt.Errorf("max memory size must be positive")
 	}
@@ -43,16 +82,39 @@ func (c *JournalConfig) ValidateConfig() error {
 		return fmt.Errorf("strength threshold must be between 0 and 1")
 	}
 	
+	if c.StrengthMinAgeDays < 0 {
+		return fmt.Errorf("strength min age days cannot be negative")
+	}
+	
+	if c.AssociationWorkers <= 0 {
+		return fmt.Errorf("association workers must be positive")
+	}
+	
+	if c.AssociationQueueSize <= 0 {
+		return fmt.Errorf("association queue size must be positive")
+	}
+	
 	return nil
 }
 
 // GetDefaults returns default configuration values
 func (c *JournalConfig) GetDefaults() map[string]any {
 	return map[string]any{
-		"journal.batch_size":             100,
-		"journal.retention_days":         30,
-		"journal.consolidation_interval": "6h",
-		"journal.max_memory_size":        10000,
-		"journal.strength_threshold":     0.1,
+		"journal.batch_size":                 100,
+		"journal.retention_days":             30,
+		"journal.retention_interval":         "24h",
+		"journal.consolidation_interval":     "6h",
+		"journal.consolidation_jitter":       0.1,
+		"journal.consolidation_check":        "1m",
+		"journal.consolidated_decay":         0.5,
+		"journal.consolidation_batch_tokens": 4096,
+		"journal.extractive_fallback":        false,
+		"journal.chunk_tokens":               512,
+		"journal.chunk_overlap_tokens":       64,
+		"journal.max_memory_size":            10000,
+		"journal.strength_threshold":         0.0,
+		"journal.strength_min_age_days":      7,
+		"journal.association_workers":        4,
+		"journal.association_queue_size":     256,
 	}
 }
\ No newline at end of file
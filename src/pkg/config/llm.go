@@ -9,12 +9,16 @@ import (
 
 // LLMConfig holds LLM configuration
 type LLMConfig struct {
-	Provider             string        `mapstructure:"provider"`               // "ollama", "openai", etc.
+	Provider             string        `mapstructure:"provider"`               // "ollama", "openai", "localai" or "local"
 	URL                  string        `mapstructure:"url"`                    // LLM service URL
+	APIKeyEnv            string        `mapstructure:"api_key_env"`            // Environment variable holding the API key (OpenAI-compatible providers)
 	EmbeddingModel       string        `mapstructure:"embedding_model"`        // Model for embeddings
 	ConsolidationModel   string        `mapstructure:"consolidation_model"`    // Model for consolidation
+	Dimension            int           `mapstructure:"dimension"`              // Embedding size for the local provider (0 uses the vector store's dimension)
 	CacheEnabled         bool          `mapstructure:"cache_enabled"`          // Enable embedding cache
 	CacheTTL             time.Duration `mapstructure:"cache_ttl"`              // Cache TTL
+	CacheMaxEntries      int           `mapstructure:"cache_max_entries"`      // Embeddings kept before the least recently used are evicted
+	CachePath            string        `mapstructure:"cache_path"`             // SQLite file persisting the cache across restarts (empty keeps it in memory)
 	Timeout              time.Duration `mapstructure:"timeout"`                // Request timeout
 	MaxRetries           int           `mapstructure:"max_retries"`            // Max retry attempts
 }
@@ -50,6 +54,14 @@ func (c *LLMConfig) ValidateConfig() error {
 		return fmt.Errorf("cache TTL must be positive when cache is enabled")
 	}
 	
+	if c.CacheMaxEntries <= 0 && c.CacheEnabled {
+		return fmt.Errorf("cache max entries must be positive when cache is enabled")
+	}
+	
+	if c.Dimension < 0 {
+		return fmt.Errorf("dimension cannot be negative")
+	}
+	
 	if c.MaxRetries < 0 {
 		return fmt.Errorf("max retries cannot be negative")
 	}
@@ -62,10 +74,13 @@ func (c *LLMConfig) GetDefaults() map[string]any {
 	return map[string]any{
 		"llm.provider":             "ollama",
 		"llm.url":                  "http://ollama:11434",
+		"llm.api_key_env":          "OPENAI_API_KEY",
 		"llm.embedding_model":      "phi3:mini",
 		"llm.consolidation_model":  "phi3:mini",
 		"llm.cache_enabled":        true,
 		"llm.cache_ttl":            "1h",
+		"llm.cache_max_entries":    10000,
+		"llm.cache_path":           "",
 		"llm.timeout":              "30s",
 		"llm.max_retries":          3,
 	}
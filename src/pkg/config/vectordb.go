@@ -9,10 +9,12 @@ import (
 
 // VectorDBConfig holds vector database configuration
 type VectorDBConfig struct {
-	Provider               string            `mapstructure:"provider"`               // "qdrant", "weaviate", etc.
-	URL                    string            `mapstructure:"url"`                    // Database URL
+	Provider               string            `mapstructure:"provider"`               // "qdrant", "sqlite", "memory", etc.
+	URL                    string            `mapstructure:"url"`                    // Database URL (file path for sqlite)
 	MemoryCollections      map[string]string `mapstructure:"memory_collections"`      // Memory type -> collection name
 	AssociationsCollection string            `mapstructure:"associations_collection"` // Association collection name
+	VersionsCollection     string            `mapstructure:"versions_collection"`     // Memory version collection name
+	ChunksCollection       string            `mapstructure:"chunks_collection"`       // Chunks of long memories (empty disables chunked storage)
 	VectorDimension        int               `mapstructure:"vector_dimension"`       // Vector embedding dimension
 	OnDiskPayload          bool              `mapstructure:"on_disk_payload"`        // Use disk storage for payloads
 	Timeout                time.Duration     `mapstructure:"timeout"`                // Connection timeout
@@ -30,7 +32,8 @@ func (c *VectorDBConfig) ValidateConfig() error {
 		return fmt.Errorf("vectordb provider cannot be empty")
 	}
 
-	if c.URL == "" {
+	// The in-memory provider runs in process and has no server to connect to
+	if c.URL == "" && c.Provider != "memory" {
 		return fmt.Errorf("vectordb URL cannot be empty")
 	}
 
@@ -66,5 +69,7 @@ func (c *VectorDBConfig) GetDefaults() map[string]any {
 			"metacognitive": "metacognitive_memories",
 		},
 		"vectordb.associations_collection": "associations",
+		"vectordb.versions_collection":     "memory_versions",
+		"vectordb.chunks_collection":       "memory_chunks",
 	}
 }
@@ -2,8 +2,10 @@ package journal
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/JaimeStill/persistent-context/pkg/models"
@@ -11,9 +13,11 @@ import (
 	"github.com/google/uuid"
 )
 
-// AssociationTracker manages relationships between memories
+// AssociationTracker manages relationships between memories.
+// It is safe for concurrent use; persistence happens outside the lock.
 type AssociationTracker struct {
 	vectorDB *vectordb.VectorDB
+	mu       sync.RWMutex
 	// In-memory association storage for fast access
 	associations map[string]*models.MemoryAssociation
 	// Index for quick lookups by source memory
@@ -32,7 +36,8 @@ func NewAssociationTracker(vectorDB vectordb.VectorDB) *AssociationTracker {
 	}
 }
 
-// CreateAssociation creates a new association between two memories
+// CreateAssociation creates a new association between two memories. The returned
+// association is a copy, so later strength updates do not race with callers reading it.
 func (at *AssociationTracker) CreateAssociation(sourceID, targetID string, associationType models.AssociationType, strength float64, metadata map[string]any) *models.MemoryAssociation {
 	association := &models.MemoryAssociation{
 		ID:        uuid.New().String(),
@@ -46,34 +51,83 @@ func (at *AssociationTracker) CreateAssociation(sourceID, targetID string, assoc
 	}
 
 	// Store association in memory for fast access
-	at.associations[association.ID] = association
-
-	// Update indexes
-	at.sourceIndex[sourceID] = append(at.sourceIndex[sourceID], association)
-	at.targetIndex[targetID] = append(at.targetIndex[targetID], association)
+	at.mu.Lock()
+	at.index(association)
+	at.mu.Unlock()
 
-	// Persist to database
+	// Persist a copy so later strength updates cannot race with the write
+	persisted := *association
 	ctx := context.Background()
-	if err := (*at.vectorDB).Associations().Store(ctx, association); err != nil {
+	if err := (*at.vectorDB).Associations().Store(ctx, &persisted); err != nil {
 		slog.Error("Failed to persist association to database", "error", err, "association_id", association.ID)
 		// Continue with in-memory storage even if persistence fails
 	}
 
-	return association
+	created := *association
+	return &created
+}
+
+// associationLoadPageSize is the page size used when hydrating the tracker from storage
+const associationLoadPageSize = 1000
+
+// Load hydrates the in-memory indexes from the association collection so
+// relationships captured before a restart remain available
+func (at *AssociationTracker) Load(ctx context.Context) (int, error) {
+	loaded := 0
+	cursor := ""
+
+	for {
+		page, nextCursor, err := (*at.vectorDB).Associations().GetAll(ctx, cursor, associationLoadPageSize)
+		if err != nil {
+			return loaded, fmt.Errorf("failed to load associations: %w", err)
+		}
+
+		// Some stores return the cursor association again as the first item of the next page
+		added := 0
+		at.mu.Lock()
+		for _, association := range page {
+			if _, exists := at.associations[association.ID]; exists {
+				continue
+			}
+			at.index(association)
+			added++
+		}
+		at.mu.Unlock()
+		loaded += added
+
+		if nextCursor == "" || nextCursor == cursor || added == 0 {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	return loaded, nil
 }
 
-// GetAssociationsForMemory returns all associations for a given memory ID
+// index adds an association to the main map and the source and target indexes; callers must hold the lock
+func (at *AssociationTracker) index(association *models.MemoryAssociation) {
+	at.associations[association.ID] = association
+	at.sourceIndex[association.SourceID] = append(at.sourceIndex[association.SourceID], association)
+	at.targetIndex[association.TargetID] = append(at.targetIndex[association.TargetID], association)
+}
+
+// GetAssociationsForMemory returns copies of all associations for a given memory ID
 func (at *AssociationTracker) GetAssociationsForMemory(memoryID string) []*models.MemoryAssociation {
+	at.mu.RLock()
+	defer at.mu.RUnlock()
+
 	var associations []*models.MemoryAssociation
 
 	// Get associations where this memory is the source
-	if sourceAssocs, exists := at.sourceIndex[memoryID]; exists {
-		associations = append(associations, sourceAssocs...)
+	for _, association := range at.sourceIndex[memoryID] {
+		copied := *association
+		associations = append(associations, &copied)
 	}
 
 	// Get associations where this memory is the target
-	if targetAssocs, exists := at.targetIndex[memoryID]; exists {
-		associations = append(associations, targetAssocs...)
+	for _, association := range at.targetIndex[memoryID] {
+		copied := *association
+		associations = append(associations, &copied)
 	}
 
 	return associations
@@ -97,18 +151,31 @@ func (at *AssociationTracker) GetRelatedMemoryIDs(memoryID string) []string {
 
 // UpdateAssociationStrength updates the strength of an existing association
 func (at *AssociationTracker) UpdateAssociationStrength(associationID string, newStrength float64) bool {
-	if association, exists := at.associations[associationID]; exists {
-		association.Strength = newStrength
-		association.UpdatedAt = time.Now()
-		return true
+	at.mu.Lock()
+	association, exists := at.associations[associationID]
+	if !exists {
+		at.mu.Unlock()
+		return false
 	}
-	return false
+	association.Strength = newStrength
+	association.UpdatedAt = time.Now()
+	persisted := *association
+	at.mu.Unlock()
+
+	// Persist the change so the store matches the in-memory indexes
+	ctx := context.Background()
+	if err := (*at.vectorDB).Associations().Store(ctx, &persisted); err != nil {
+		slog.Error("Failed to persist association strength", "error", err, "association_id", associationID)
+	}
+	return true
 }
 
 // RemoveAssociation removes an association and updates indexes
 func (at *AssociationTracker) RemoveAssociation(associationID string) bool {
+	at.mu.Lock()
 	association, exists := at.associations[associationID]
 	if !exists {
+		at.mu.Unlock()
 		return false
 	}
 
@@ -124,10 +191,46 @@ func (at *AssociationTracker) RemoveAssociation(associationID string) bool {
 	if targetAssocs, exists := at.targetIndex[association.TargetID]; exists {
 		at.targetIndex[association.TargetID] = removeAssociationFromSlice(targetAssocs, associationID)
 	}
+	at.mu.Unlock()
+
+	// Remove from database
+	ctx := context.Background()
+	if err := (*at.vectorDB).Associations().Delete(ctx, []string{associationID}); err != nil {
+		slog.Error("Failed to delete association from database", "error", err, "association_id", associationID)
+	}
 
 	return true
 }
 
+// RemoveMemory removes every association referencing a memory from the indexes and the
+// association collection, returning the associations that were indexed in memory
+func (at *AssociationTracker) RemoveMemory(ctx context.Context, memoryID string) ([]*models.MemoryAssociation, error) {
+	at.mu.Lock()
+	var removed []*models.MemoryAssociation
+	removed = append(removed, at.sourceIndex[memoryID]...)
+	removed = append(removed, at.targetIndex[memoryID]...)
+
+	for _, association := range removed {
+		delete(at.associations, association.ID)
+		if association.SourceID != memoryID {
+			at.sourceIndex[association.SourceID] = removeAssociationFromSlice(at.sourceIndex[association.SourceID], association.ID)
+		}
+		if association.TargetID != memoryID {
+			at.targetIndex[association.TargetID] = removeAssociationFromSlice(at.targetIndex[association.TargetID], association.ID)
+		}
+	}
+	delete(at.sourceIndex, memoryID)
+	delete(at.targetIndex, memoryID)
+	at.mu.Unlock()
+
+	// Delete from the store as well, which also covers associations that were never loaded
+	if err := (*at.vectorDB).Associations().DeleteByMemoryID(ctx, memoryID); err != nil {
+		return removed, fmt.Errorf("failed to delete associations for memory %s: %w", memoryID, err)
+	}
+
+	return removed, nil
+}
+
 // removeAssociationFromSlice removes an association from a slice by ID
 func removeAssociationFromSlice(associations []*models.MemoryAssociation, associationID string) []*models.MemoryAssociation {
 	for i, assoc := range associations {
@@ -0,0 +1,391 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/JaimeStill/persistent-context/pkg/vectordb"
+	"github.com/google/uuid"
+)
+
+// capturedEmbedding is the embedding of one captured memory and, when its content was
+// too long to embed whole, the chunks it was split into with their embeddings
+type capturedEmbedding struct {
+	embedding       []float32
+	chunks          []string
+	chunkEmbeddings [][]float32
+}
+
+// chunkingEnabled reports whether long content is embedded in chunks
+func (vj *VectorJournal) chunkingEnabled() bool {
+	return vj.config.ChunkTokens > 0 && vj.vectorDBConfig.ChunksCollection != ""
+}
+
+// chunk splits content that exceeds the chunk size, returning nil for content that is
+// embedded whole
+func (vj *VectorJournal) chunk(content string) []string {
+	if !vj.chunkingEnabled() || estimateTokens(content) <= vj.config.ChunkTokens {
+		return nil
+	}
+
+	chunks := chunkContent(content, vj.config.ChunkTokens*charsPerToken, vj.config.ChunkOverlapTokens*charsPerToken)
+	if len(chunks) < 2 {
+		return nil
+	}
+	return chunks
+}
+
+// embedCaptures embeds captured contents with a single batch request. Long contents are
+// chunked and each chunk embedded; the memory itself is then embedded as the normalized
+// mean of its chunks so it still ranks sensibly in whole-memory comparisons.
+func (vj *VectorJournal) embedCaptures(ctx context.Context, contents []string) ([]capturedEmbedding, error) {
+	captured := make([]capturedEmbedding, len(contents))
+	var texts []string
+	for i, content := range contents {
+		captured[i].chunks = vj.chunk(content)
+		if captured[i].chunks == nil {
+			texts = append(texts, content)
+		} else {
+			texts = append(texts, captured[i].chunks...)
+		}
+	}
+
+	embeddings, err := vj.llmClient.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	next := 0
+	for i := range captured {
+		if captured[i].chunks == nil {
+			captured[i].embedding = embeddings[next]
+			next++
+			continue
+		}
+
+		captured[i].chunkEmbeddings = embeddings[next : next+len(captured[i].chunks)]
+		captured[i].embedding = meanEmbedding(captured[i].chunkEmbeddings)
+		next += len(captured[i].chunks)
+	}
+
+	return captured, nil
+}
+
+// storeChunks stores the chunks of a memory, replacing any it previously had. Chunk IDs
+// are derived from the memory ID and chunk index, so a memory's chunks can be found and
+// deleted from its chunk_count alone.
+func (vj *VectorJournal) storeChunks(ctx context.Context, parent *models.MemoryEntry, chunks []string, embeddings [][]float32) error {
+	previous := chunkCount(parent)
+
+	for i, chunk := range chunks {
+		entry := &models.MemoryEntry{
+			ID:        chunkID(parent.ID, i),
+			Type:      models.TypeChunk,
+			Content:   chunk,
+			Embedding: embeddings[i],
+			Metadata: map[string]any{
+				"parent_id":   parent.ID,
+				"chunk_index": i,
+				"chunk_count": len(chunks),
+			},
+			CreatedAt:      parent.CreatedAt,
+			AccessedAt:     parent.CreatedAt,
+			Strength:       1.0,
+			AssociationIDs: []string{},
+		}
+		if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+			return fmt.Errorf("failed to store chunk %d of memory %s: %w", i, parent.ID, err)
+		}
+	}
+
+	if previous > len(chunks) {
+		if err := vj.vectorDB.Memories().Delete(ctx, models.TypeChunk, chunkIDs(parent.ID, len(chunks), previous)); err != nil {
+			return fmt.Errorf("failed to delete stale chunks of memory %s: %w", parent.ID, err)
+		}
+	}
+
+	if len(chunks) == 0 {
+		delete(parent.Metadata, "chunk_count")
+	} else {
+		parent.Metadata["chunk_count"] = len(chunks)
+	}
+	return nil
+}
+
+// deleteChunks removes every chunk stored for a memory
+func (vj *VectorJournal) deleteChunks(ctx context.Context, parent *models.MemoryEntry) error {
+	count := chunkCount(parent)
+	if count == 0 || vj.vectorDBConfig.ChunksCollection == "" {
+		return nil
+	}
+
+	if err := vj.vectorDB.Memories().Delete(ctx, models.TypeChunk, chunkIDs(parent.ID, 0, count)); err != nil {
+		return fmt.Errorf("failed to delete chunks of memory %s: %w", parent.ID, err)
+	}
+	return nil
+}
+
+// withChunkMatches merges vector matches against the chunks of long episodic memories into
+// the direct episodic matches. Chunk hits are collapsed onto their parent memory, which takes
+// its best chunk's similarity when that beats its own and carries that chunk as MatchedChunk.
+// Parents are loaded by ID, so the filter is applied to them rather than to their chunks.
+func (vj *VectorJournal) withChunkMatches(ctx context.Context, direct []*models.MemoryEntry, embedding []float32, candidates uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	if vj.vectorDBConfig.ChunksCollection == "" {
+		return direct, nil
+	}
+
+	chunks, err := vj.vectorDB.Memories().Query(ctx, models.TypeChunk, embedding, candidates, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory chunks: %w", err)
+	}
+
+	merged := make(map[string]*models.MemoryEntry, len(direct))
+	for _, entry := range direct {
+		merged[entry.ID] = entry
+	}
+
+	// Results are ordered by similarity, so the first chunk seen for a parent is its best
+	missing := make(map[string]bool)
+	for _, chunk := range chunks {
+		parentID, _ := chunk.Metadata["parent_id"].(string)
+		if parentID == "" || missing[parentID] {
+			continue
+		}
+
+		parent, exists := merged[parentID]
+		if !exists {
+			parent, err = vj.vectorDB.Memories().Retrieve(ctx, models.TypeEpisodic, parentID)
+			if errors.Is(err, vectordb.ErrMemoryNotFound) {
+				slog.Debug("Skipping chunk of missing memory", "chunk_id", chunk.ID, "parent_id", parentID)
+				missing[parentID] = true
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve memory %s for chunk match: %w", parentID, err)
+			}
+			if !vectordb.MatchesFilter(parent, filter) {
+				missing[parentID] = true
+				continue
+			}
+			merged[parentID] = parent
+		}
+
+		if chunk.Similarity > parent.Similarity {
+			parent.Similarity = chunk.Similarity
+			parent.MatchedChunk = chunk.Content
+		}
+	}
+
+	entries := make([]*models.MemoryEntry, 0, len(merged))
+	for _, entry := range merged {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Similarity == entries[j].Similarity {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].Similarity > entries[j].Similarity
+	})
+
+	if uint64(len(entries)) > candidates {
+		entries = entries[:candidates]
+	}
+	return entries, nil
+}
+
+// chunkCount returns how many chunks a memory was stored with
+func chunkCount(entry *models.MemoryEntry) int {
+	count, _ := metadataInt(entry.Metadata["chunk_count"])
+	return int(count)
+}
+
+// chunkID derives the ID of a memory's chunk. It is a name-based UUID so every provider
+// accepts it.
+func chunkID(parentID string, index int) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(fmt.Sprintf("%s/chunk/%d", parentID, index))).String()
+}
+
+// chunkIDs returns the IDs of a memory's chunks from index from up to (not including) to
+func chunkIDs(parentID string, from, to int) []string {
+	ids := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		ids = append(ids, chunkID(parentID, i))
+	}
+	return ids
+}
+
+// meanEmbedding averages embeddings and normalizes the result to unit length
+func meanEmbedding(embeddings [][]float32) []float32 {
+	mean := make([]float32, len(embeddings[0]))
+	for _, embedding := range embeddings {
+		for i, value := range embedding {
+			mean[i] += value
+		}
+	}
+
+	var norm float64
+	for _, value := range mean {
+		norm += float64(value) * float64(value)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range mean {
+			mean[i] *= scale
+		}
+	}
+	return mean
+}
+
+// chunkContent splits content into chunks of at most maxChars characters. Breaks are
+// placed at markdown headings, code fence boundaries and blank lines where possible, then
+// at line ends, and only mid-line for lines longer than a chunk. Each chunk after the first
+// starts with up to overlapChars of the previous chunk's trailing lines, so text spanning a
+// break can still be matched from either side.
+func chunkContent(content string, maxChars, overlapChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	var lines []string // Lines of the current chunk, kept for the overlap
+	fresh := 0         // Lines added to the current chunk since it was seeded
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+		current.Reset()
+
+		// Seed the next chunk with whole trailing lines that fit in the overlap
+		start, size := len(lines), 0
+		for start > 0 && size+len(lines[start-1]) <= overlapChars {
+			start--
+			size += len(lines[start])
+		}
+		lines = append([]string{}, lines[start:]...)
+		for _, line := range lines {
+			current.WriteString(line)
+		}
+		fresh = 0
+	}
+
+	// makeRoom closes the current chunk if text does not fit after it, dropping the
+	// overlap too when text would not fit after that either
+	makeRoom := func(size int) {
+		if current.Len()+size <= maxChars {
+			return
+		}
+		if fresh > 0 {
+			flush()
+		}
+		if current.Len()+size > maxChars {
+			current.Reset()
+			lines = nil
+		}
+	}
+
+	for _, block := range splitBlocks(content) {
+		// Start a block that would straddle a break in a new chunk instead
+		makeRoom(len(block))
+
+		for _, line := range splitLines(block, maxChars) {
+			makeRoom(len(line))
+			current.WriteString(line)
+			lines = append(lines, line)
+			fresh++
+		}
+	}
+
+	// A final chunk holding only the previous chunk's overlap would add nothing
+	if fresh > 0 {
+		flush()
+	}
+
+	return chunks
+}
+
+// splitBlocks splits content into blocks that should stay together: runs of lines
+// separated by blank lines and fenced code blocks, with markdown headings kept with the
+// block after them. Blank lines inside a fence still separate blocks so long code can be
+// split between declarations.
+func splitBlocks(content string) []string {
+	var blocks []string
+	var current strings.Builder
+	inFence := false
+	heading := false // The current block so far is only a heading, which stays with what follows
+
+	closeBlock := func() {
+		if current.Len() > 0 {
+			blocks = append(blocks, current.String())
+			current.Reset()
+		}
+		heading = false
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		fence := strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+
+		switch {
+		case fence && !inFence:
+			closeBlock()
+			current.WriteString(line)
+			inFence = true
+		case fence:
+			current.WriteString(line)
+			closeBlock()
+			inFence = false
+		case trimmed == "":
+			current.WriteString(line)
+			if !heading {
+				closeBlock()
+			}
+		case !inFence && isHeading(trimmed):
+			closeBlock()
+			current.WriteString(line)
+			heading = true
+		default:
+			current.WriteString(line)
+			heading = false
+		}
+	}
+	closeBlock()
+
+	return blocks
+}
+
+// isHeading reports whether a trimmed line is an ATX markdown heading
+func isHeading(line string) bool {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	return level >= 1 && level <= 6 && (len(line) == level || line[level] == ' ')
+}
+
+// splitLines splits a block into its lines, breaking any line longer than maxChars at
+// the last space before the limit, or at the limit itself when there is none
+func splitLines(block string, maxChars int) []string {
+	var lines []string
+	for _, line := range strings.SplitAfter(block, "\n") {
+		for len(line) > maxChars {
+			cut := strings.LastIndexByte(line[:maxChars], ' ') + 1
+			if cut == 0 {
+				cut = maxChars
+				for cut > 0 && !utf8.RuneStart(line[cut]) {
+					cut--
+				}
+				if cut == 0 {
+					cut = maxChars
+				}
+			}
+			lines = append(lines, line[:cut])
+			line = line[cut:]
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,172 @@
+package journal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+)
+
+// numberedLines returns n lines of the form "<prefix> line <i>"
+func numberedLines(prefix string, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%s line %d\n", prefix, i)
+	}
+	return b.String()
+}
+
+func TestChunkContent(t *testing.T) {
+	fence := "```go\nfunc main() {\n\tfmt.Println(\"kept together\")\n}\n```\n"
+
+	tests := []struct {
+		name         string
+		content      string
+		maxChars     int
+		overlapChars int
+		wantChunks   int      // Expected number of chunks, or 0 to only check the invariants
+		whole        []string // Text that must appear unbroken in a single chunk
+	}{
+		{
+			name:       "content within the limit",
+			content:    "a short note\n\nwith two paragraphs\n",
+			maxChars:   64,
+			wantChunks: 1,
+		},
+		{
+			name:         "oversized single block",
+			content:      numberedLines("paragraph", 40),
+			maxChars:     100,
+			overlapChars: 20,
+		},
+		{
+			name:     "oversized single line",
+			content:  strings.Repeat("word ", 100),
+			maxChars: 64,
+		},
+		{
+			name:         "overlap of the chunk size",
+			content:      numberedLines("overlap", 30),
+			maxChars:     60,
+			overlapChars: 60,
+		},
+		{
+			name:         "overlap beyond the chunk size",
+			content:      numberedLines("overlap", 30),
+			maxChars:     60,
+			overlapChars: 200,
+		},
+		{
+			name:     "fenced code kept whole",
+			content:  numberedLines("intro", 4) + "\n" + fence + "\n" + numberedLines("outro", 4),
+			maxChars: 80,
+			whole:    []string{strings.TrimSpace(fence)},
+		},
+		{
+			name:     "heading kept with its section",
+			content:  numberedLines("intro", 3) + "\n## Section\n\n" + numberedLines("body", 2),
+			maxChars: 60,
+			whole:    []string{"## Section\n\nbody line 0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunkContent(tt.content, tt.maxChars, tt.overlapChars)
+
+			if tt.wantChunks > 0 && len(chunks) != tt.wantChunks {
+				t.Fatalf("split into %d chunks, want %d: %q", len(chunks), tt.wantChunks, chunks)
+			}
+			if len(chunks) == 0 {
+				t.Fatal("split into no chunks")
+			}
+
+			for i, chunk := range chunks {
+				if len(chunk) > tt.maxChars {
+					t.Errorf("chunk %d is %d characters, over the %d limit", i, len(chunk), tt.maxChars)
+				}
+			}
+
+			// Every word is kept in at least one chunk
+			joined := strings.Join(chunks, " ")
+			for _, line := range strings.Split(tt.content, "\n") {
+				for _, word := range strings.Fields(line) {
+					if !strings.Contains(joined, word) {
+						t.Errorf("word %q of line %q is in no chunk", word, line)
+					}
+				}
+			}
+
+			for _, text := range tt.whole {
+				if !slices.ContainsFunc(chunks, func(chunk string) bool { return strings.Contains(chunk, text) }) {
+					t.Errorf("no chunk holds %q whole: %q", text, chunks)
+				}
+			}
+
+			// Re-chunking the same content gives the same chunks
+			if again := chunkContent(tt.content, tt.maxChars, tt.overlapChars); !slices.Equal(again, chunks) {
+				t.Errorf("re-chunking gave %q, want %q", again, chunks)
+			}
+		})
+	}
+}
+
+func TestChunkOverlap(t *testing.T) {
+	chunks := chunkContent(numberedLines("overlap", 12), 60, 20)
+	if len(chunks) < 2 {
+		t.Fatalf("split into %d chunks, want several", len(chunks))
+	}
+
+	for i := 1; i < len(chunks); i++ {
+		previous := strings.Split(chunks[i-1], "\n")
+		if last := previous[len(previous)-1]; !strings.HasPrefix(chunks[i], last) {
+			t.Errorf("chunk %d starts %q, want the overlap %q", i, chunks[i], last)
+		}
+	}
+}
+
+func TestChunkThreshold(t *testing.T) {
+	vj := &VectorJournal{
+		config:         &config.JournalConfig{ChunkTokens: 16, ChunkOverlapTokens: 2},
+		vectorDBConfig: &config.VectorDBConfig{ChunksCollection: "memory_chunks"},
+	}
+
+	atLimit := strings.Repeat("x", 16*charsPerToken)
+	if chunks := vj.chunk(atLimit); chunks != nil {
+		t.Errorf("content at chunk_tokens split into %d chunks, want it embedded whole", len(chunks))
+	}
+
+	over := numberedLines("over", 10)
+	if chunks := vj.chunk(over); len(chunks) < 2 {
+		t.Errorf("content over chunk_tokens split into %d chunks, want several", len(chunks))
+	}
+
+	vj.config.ChunkTokens = 0
+	if chunks := vj.chunk(over); chunks != nil {
+		t.Errorf("content split into %d chunks with chunking disabled", len(chunks))
+	}
+}
+
+func TestChunkIDsAreStable(t *testing.T) {
+	parent := "5f0c1b8e-2f43-4d8a-9a57-0e1b2c3d4e5f"
+
+	ids := chunkIDs(parent, 0, 3)
+	if again := chunkIDs(parent, 0, 3); !slices.Equal(again, ids) {
+		t.Errorf("chunk IDs changed between calls: %v, then %v", ids, again)
+	}
+	if tail := chunkIDs(parent, 1, 3); !slices.Equal(tail, ids[1:]) {
+		t.Errorf("chunk IDs from index 1 are %v, want %v", tail, ids[1:])
+	}
+
+	other := chunkIDs("7a1d2e3f-4b5c-4d6e-8f70-8192a3b4c5d6", 0, 3)
+	for i, id := range ids {
+		if slices.Contains(ids[i+1:], id) {
+			t.Errorf("chunk ID %s repeats within a memory", id)
+		}
+		if slices.Contains(other, id) {
+			t.Errorf("chunk ID %s is shared with another memory", id)
+		}
+	}
+}
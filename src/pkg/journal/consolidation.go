@@ -0,0 +1,504 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/JaimeStill/persistent-context/pkg/vectordb"
+	"github.com/google/uuid"
+)
+
+// ErrNotConsolidated is returned when rolling back a memory that was not created by consolidation
+var ErrNotConsolidated = errors.New("memory was not created by consolidation")
+
+// ErrAlreadyConsolidated is returned when every memory passed to ConsolidateMemories
+// was consolidated or deleted after the caller selected it
+var ErrAlreadyConsolidated = errors.New("memories already consolidated")
+
+// IsConsolidated reports whether a memory has already been consolidated into semantic knowledge
+func IsConsolidated(entry *models.MemoryEntry) bool {
+	_, ok := metadataInt(entry.Metadata["consolidated_at"])
+	return ok
+}
+
+// IsExtractive reports whether a consolidated memory is an extractive summary stored
+// because the model failed; its sources were left unconsolidated and name it in their
+// extractive_into metadata until a later consolidation replaces it
+func IsExtractive(entry *models.MemoryEntry) bool {
+	extractive, _ := entry.Metadata["extractive"].(bool)
+	return extractive
+}
+
+// UnconsolidatedFilter matches memories that have not been consolidated yet
+func UnconsolidatedFilter() *models.MemoryFilter {
+	var zero float64
+	return &models.MemoryFilter{
+		MustNot: []models.FilterCondition{
+			{Field: "consolidated_at", Range: &models.RangeFilter{GT: &zero}},
+		},
+	}
+}
+
+// linkSources records a derived_from association from a consolidated memory to each of its sources
+func (vj *VectorJournal) linkSources(consolidatedID string, sourceIDs []string, consolidatedAt time.Time) {
+	for _, sourceID := range sourceIDs {
+		vj.associations.CreateAssociation(consolidatedID, sourceID, models.AssociationDerivedFrom, 1.0, map[string]any{
+			"consolidated_at": consolidatedAt.Unix(),
+		})
+	}
+}
+
+// storeKnowledge stores each extracted concept, pattern and insight as a memory of the
+// matching type, linked to the memories cited as its evidence, and returns their IDs.
+// Records whose type has no configured collection, or that fail to store, are skipped.
+func (vj *VectorJournal) storeKnowledge(ctx context.Context, knowledge *models.Knowledge, consolidationID string, consolidatedAt time.Time) []string {
+	var entries []*models.MemoryEntry
+
+	for _, concept := range knowledge.Concepts {
+		entry := knowledgeEntry(models.TypeSemantic, "concept", fmt.Sprintf("%s: %s", concept.Name, concept.Definition), concept.CreatedFrom)
+		entry.Metadata["name"] = concept.Name
+		if len(concept.Examples) > 0 {
+			entry.Metadata["examples"] = concept.Examples
+		}
+		concept.ID = entry.ID
+		entries = append(entries, entry)
+	}
+
+	for _, pattern := range knowledge.Patterns {
+		content := fmt.Sprintf("When %s: %s", pattern.Trigger, strings.Join(pattern.Actions, "; "))
+		entry := knowledgeEntry(models.TypeProcedural, "pattern", content, pattern.Evidence)
+		entry.Metadata["trigger"] = pattern.Trigger
+		entry.Metadata["actions"] = pattern.Actions
+		pattern.ID = entry.ID
+		entries = append(entries, entry)
+	}
+
+	for _, insight := range knowledge.Insights {
+		entry := knowledgeEntry(models.TypeMetacognitive, "insight", insight.Description, insight.Evidence)
+		entry.Metadata["insight_type"] = insight.Type
+		entry.Metadata["confidence"] = float64(insight.Confidence)
+		if insight.Confidence > 0 {
+			entry.Strength = insight.Confidence
+		}
+		insight.ID = entry.ID
+		entries = append(entries, entry)
+	}
+
+	var stored []string
+	for _, entry := range entries {
+		if _, configured := vj.vectorDBConfig.MemoryCollections[string(entry.Type)]; !configured {
+			slog.Warn("Skipping knowledge record without a configured collection", "type", entry.Type)
+			continue
+		}
+
+		entry.Metadata["consolidation_id"] = consolidationID
+		entry.Metadata["consolidation_timestamp"] = consolidatedAt.Unix()
+		entry.CreatedAt = consolidatedAt
+		entry.AccessedAt = consolidatedAt
+
+		if err := vj.storeKnowledgeEntry(ctx, entry); err != nil {
+			slog.Warn("Failed to store knowledge record",
+				"type", entry.Type,
+				"kind", entry.Metadata["knowledge_kind"],
+				"error", err)
+			continue
+		}
+
+		vj.linkSources(entry.ID, metadataStrings(entry.Metadata["consolidated_from"]), consolidatedAt)
+		stored = append(stored, entry.ID)
+	}
+
+	return stored
+}
+
+// storeKnowledgeEntry embeds and stores a knowledge record
+func (vj *VectorJournal) storeKnowledgeEntry(ctx context.Context, entry *models.MemoryEntry) error {
+	embedding, err := vj.llmClient.GenerateEmbedding(ctx, entry.Content)
+	if err != nil {
+		return fmt.Errorf("failed to generate embedding: %w", err)
+	}
+	entry.Embedding = embedding
+
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		return fmt.Errorf("failed to store %s memory: %w", entry.Type, err)
+	}
+	return nil
+}
+
+// knowledgeEntry creates the memory for an extracted knowledge record
+func knowledgeEntry(memType models.MemoryType, kind string, content string, evidence []string) *models.MemoryEntry {
+	return &models.MemoryEntry{
+		ID:      uuid.New().String(),
+		Type:    memType,
+		Content: content,
+		Metadata: map[string]any{
+			"knowledge_kind":    kind,
+			"consolidated_from": evidence,
+		},
+		Strength: 1.0,
+	}
+}
+
+// markConsolidated records on each source memory the semantic memory it was consolidated
+// into and when, and weakens it by ConsolidatedDecay so retention can reclaim it once its
+// knowledge lives on in the semantic memory. Failures are logged since the semantic
+// memory has already been stored.
+func (vj *VectorJournal) markConsolidated(ctx context.Context, sources []*models.MemoryEntry, semanticID string, consolidatedAt time.Time) {
+	for _, source := range sources {
+		if err := vj.markSourceConsolidated(ctx, source.ID, semanticID, consolidatedAt); err != nil {
+			slog.Warn("Failed to mark memory as consolidated",
+				"memory_id", source.ID,
+				"semantic_id", semanticID,
+				"error", err)
+		}
+	}
+}
+
+// markSourceConsolidated reloads a source memory and records its consolidation state;
+// sources deleted in the meantime are skipped
+func (vj *VectorJournal) markSourceConsolidated(ctx context.Context, id, semanticID string, consolidatedAt time.Time) error {
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]any)
+	}
+
+	into := metadataStrings(entry.Metadata["consolidated_into"])
+	if !slices.Contains(into, semanticID) {
+		into = append(slices.Clone(into), semanticID)
+	}
+	// Keep the original strength so a rollback can restore it
+	if _, exists := entry.Metadata["strength_before_consolidation"]; !exists {
+		entry.Metadata["strength_before_consolidation"] = float64(entry.Strength)
+	}
+	entry.Metadata["consolidated_into"] = into
+	entry.Metadata["consolidated_at"] = consolidatedAt.Unix()
+	entry.Strength *= float32(vj.config.ConsolidatedDecay)
+	delete(entry.Metadata, "extractive_into") // Retired once this consolidation is stored
+
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update memory %s: %w", id, err)
+	}
+	return nil
+}
+
+// markExtractive records on each source the extractive summary standing in for its
+// consolidation, so a later consolidation of the source can retire the summary. The
+// sources stay unconsolidated. Failures are logged since the summary has already been stored.
+func (vj *VectorJournal) markExtractive(ctx context.Context, sources []*models.MemoryEntry, summaryID string) {
+	for _, source := range sources {
+		if err := vj.markSourceExtractive(ctx, source.ID, summaryID); err != nil {
+			slog.Warn("Failed to record extractive summary on memory",
+				"memory_id", source.ID,
+				"summary_id", summaryID,
+				"error", err)
+		}
+	}
+}
+
+// markSourceExtractive reloads a source memory and points it at its extractive summary;
+// sources deleted in the meantime are skipped
+func (vj *VectorJournal) markSourceExtractive(ctx context.Context, id, summaryID string) error {
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]any)
+	}
+	entry.Metadata["extractive_into"] = summaryID
+
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		return fmt.Errorf("failed to update memory %s: %w", id, err)
+	}
+	return nil
+}
+
+// extractiveSummaryIDs returns the extractive summaries currently standing in for the
+// consolidation of the given sources
+func extractiveSummaryIDs(sources []*models.MemoryEntry) []string {
+	var ids []string
+	for _, source := range sources {
+		if id, ok := source.Metadata["extractive_into"].(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// retireExtractive deletes extractive summaries, with their knowledge records, once a
+// newer consolidation of their sources has been stored. Summaries already gone are
+// skipped and failures are logged since the newer memory is in place.
+func (vj *VectorJournal) retireExtractive(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		entry, err := vj.locateMemory(ctx, id)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			continue
+		}
+		if err == nil && !IsExtractive(entry) {
+			continue
+		}
+		if err == nil {
+			_, err = vj.deleteConsolidated(ctx, entry)
+		}
+		if err != nil {
+			slog.Warn("Failed to retire extractive summary", "summary_id", id, "error", err)
+			continue
+		}
+
+		slog.Info("Retired superseded extractive summary", "summary_id", id)
+	}
+}
+
+// unconsolidatedSources reloads the memories selected for consolidation and keeps
+// those that still exist and have not been consolidated since they were selected
+func (vj *VectorJournal) unconsolidatedSources(ctx context.Context, memories []*models.MemoryEntry) ([]*models.MemoryEntry, error) {
+	sources := make([]*models.MemoryEntry, 0, len(memories))
+	for _, memory := range memories {
+		current, err := vj.locateMemory(ctx, memory.ID)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to reload memory for consolidation: %w", err)
+		}
+		if !IsConsolidated(current) {
+			sources = append(sources, current)
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%w: %d selected memories", ErrAlreadyConsolidated, len(memories))
+	}
+	return sources, nil
+}
+
+// sourceIDs returns the IDs of the memories a consolidated memory was derived from,
+// following derived_from associations and the consolidated_from metadata of entries
+// written before those associations existed
+func (vj *VectorJournal) sourceIDs(entry *models.MemoryEntry) []string {
+	ids := slices.Clone(metadataStrings(entry.Metadata["consolidated_from"]))
+	for _, association := range vj.associations.GetAssociationsForMemory(entry.ID) {
+		if association.Type == models.AssociationDerivedFrom && association.SourceID == entry.ID {
+			ids = append(ids, association.TargetID)
+		}
+	}
+
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
+
+// GetLineage walks a memory back through every consolidation it came from. Sources
+// deleted since are reported as missing; embeddings are omitted. The returned error
+// wraps vectordb.ErrMemoryNotFound when the memory itself does not exist.
+func (vj *VectorJournal) GetLineage(ctx context.Context, id string) (*models.LineageNode, error) {
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return vj.lineageNode(ctx, entry, map[string]bool{id: true})
+}
+
+// lineageNode builds the lineage below a memory; visited guards against cycles
+func (vj *VectorJournal) lineageNode(ctx context.Context, entry *models.MemoryEntry, visited map[string]bool) (*models.LineageNode, error) {
+	entry.Embedding = nil
+	node := &models.LineageNode{
+		ID:     entry.ID,
+		Memory: entry,
+	}
+
+	for _, sourceID := range vj.sourceIDs(entry) {
+		if visited[sourceID] {
+			continue
+		}
+		visited[sourceID] = true
+
+		source, err := vj.locateMemory(ctx, sourceID)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			node.Sources = append(node.Sources, &models.LineageNode{ID: sourceID, Missing: true})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve source memory %s: %w", sourceID, err)
+		}
+
+		child, err := vj.lineageNode(ctx, source, visited)
+		if err != nil {
+			return nil, err
+		}
+		node.Sources = append(node.Sources, child)
+	}
+
+	return node, nil
+}
+
+// RollbackConsolidation undoes a consolidation: the consolidated memory and any
+// knowledge records extracted with it are deleted, and each source that was
+// consolidated into it and into nothing else becomes eligible for consolidation again
+// with its original strength. The returned error wraps ErrNotConsolidated when the memory has
+// no sources.
+func (vj *VectorJournal) RollbackConsolidation(ctx context.Context, id string) (*models.RollbackResult, error) {
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIDs := vj.sourceIDs(entry)
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNotConsolidated, id)
+	}
+
+	forgotten, err := vj.deleteConsolidated(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RollbackResult{
+		ConsolidatedID: id,
+		Restored:       []string{},
+		Forget:         forgotten,
+	}
+
+	for _, sourceID := range sourceIDs {
+		state, err := vj.unmarkConsolidated(ctx, sourceID, id)
+		switch {
+		case errors.Is(err, vectordb.ErrMemoryNotFound):
+			result.Missing = append(result.Missing, sourceID)
+		case err != nil:
+			return result, fmt.Errorf("failed to restore source memory %s: %w", sourceID, err)
+		case state == sourceRestored:
+			result.Restored = append(result.Restored, sourceID)
+		case state == sourceStillConsolidated:
+			result.StillConsolidated = append(result.StillConsolidated, sourceID)
+		default:
+			result.Unaffected = append(result.Unaffected, sourceID)
+		}
+	}
+
+	slog.Info("Consolidation rolled back",
+		"id", id,
+		"restored", len(result.Restored),
+		"still_consolidated", len(result.StillConsolidated),
+		"missing", len(result.Missing),
+		"unaffected", len(result.Unaffected))
+
+	return result, nil
+}
+
+// deleteConsolidated deletes a consolidated memory together with the concepts, patterns
+// and insights extracted with it
+func (vj *VectorJournal) deleteConsolidated(ctx context.Context, entry *models.MemoryEntry) (*models.ForgetResult, error) {
+	forgotten, err := vj.DeleteMemory(ctx, entry.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete consolidated memory %s: %w", entry.ID, err)
+	}
+
+	if records := metadataStrings(entry.Metadata["knowledge_records"]); len(records) > 0 {
+		removed, err := vj.ForgetMemories(ctx, records)
+		if removed != nil {
+			forgotten.Deleted = append(forgotten.Deleted, removed.Deleted...)
+			forgotten.AssociationsRemoved += removed.AssociationsRemoved
+			forgotten.NeighborsUpdated += removed.NeighborsUpdated
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete knowledge records of %s: %w", entry.ID, err)
+		}
+	}
+
+	return forgotten, nil
+}
+
+// sourceState is a source memory's consolidation state after a rollback
+type sourceState int
+
+const (
+	sourceUnaffected        sourceState = iota // The source was not consolidated into the rolled-back memory
+	sourceRestored                             // The source is unconsolidated again
+	sourceStillConsolidated                    // The source remains consolidated into other memories
+)
+
+// unmarkConsolidated removes a rolled-back memory from a source's consolidation state.
+// Only sources whose consolidated_into lists the memory change; other consolidations of
+// the source are kept. A source left unconsolidated gets its strength restored. A
+// source that only names the memory as its extractive summary drops that reference.
+func (vj *VectorJournal) unmarkConsolidated(ctx context.Context, id, consolidatedID string) (sourceState, error) {
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return sourceUnaffected, err
+	}
+
+	into := metadataStrings(entry.Metadata["consolidated_into"])
+	if !slices.Contains(into, consolidatedID) {
+		if extractiveInto, _ := entry.Metadata["extractive_into"].(string); extractiveInto != consolidatedID {
+			return sourceUnaffected, nil
+		}
+		delete(entry.Metadata, "extractive_into")
+		if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+			return sourceUnaffected, fmt.Errorf("failed to update memory %s: %w", id, err)
+		}
+		return sourceUnaffected, nil
+	}
+
+	into = slices.DeleteFunc(slices.Clone(into), func(other string) bool {
+		return other == consolidatedID
+	})
+
+	state := sourceStillConsolidated
+	if len(into) == 0 {
+		state = sourceRestored
+		if strength, ok := metadataFloat(entry.Metadata["strength_before_consolidation"]); ok {
+			entry.Strength = float32(strength)
+		}
+		delete(entry.Metadata, "consolidated_into")
+		delete(entry.Metadata, "consolidated_at")
+		delete(entry.Metadata, "strength_before_consolidation")
+	} else {
+		entry.Metadata["consolidated_into"] = into
+	}
+
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		return sourceUnaffected, fmt.Errorf("failed to update memory %s: %w", id, err)
+	}
+	return state, nil
+}
+
+// metadataFloat reads a numeric metadata value regardless of how the store decoded it
+func metadataFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,67 @@
+package journal
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/persistent-context/pkg/llm"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// conceptModel extracts one concept citing every memory it is given
+type conceptModel struct {
+	llm.LLM
+}
+
+func (conceptModel) ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	return &models.Knowledge{
+		Summary: "Deploys fail when migrations time out.",
+		Concepts: []*models.Concept{{
+			Name:        "migration timeout",
+			Definition:  "the limit a schema migration must finish within",
+			CreatedFrom: extractMemoryIDs(memories),
+		}},
+	}, nil
+}
+
+func TestRollbackKnowledgeRecordKeepsSummarySources(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	vj.llmClient = conceptModel{vj.llmClient}
+	ctx := context.Background()
+	semantic, sources := consolidateSources(t, vj)
+
+	records := metadataStrings(semantic.Metadata["knowledge_records"])
+	if len(records) != 1 {
+		t.Fatalf("consolidation stored %d knowledge records, want 1", len(records))
+	}
+
+	result, err := vj.RollbackConsolidation(ctx, records[0])
+	if err != nil {
+		t.Fatalf("failed to roll back knowledge record: %v", err)
+	}
+	if len(result.Restored) != 0 || len(result.StillConsolidated) != 0 {
+		t.Errorf("record rollback restored %v and kept %v, want neither", result.Restored, result.StillConsolidated)
+	}
+	if len(result.Unaffected) != len(sources) {
+		t.Errorf("record rollback left %d sources unaffected, want %d", len(result.Unaffected), len(sources))
+	}
+
+	for _, source := range sources {
+		stored, err := vj.locateMemory(ctx, source.ID)
+		if err != nil {
+			t.Fatalf("failed to reload source: %v", err)
+		}
+		if into := metadataStrings(stored.Metadata["consolidated_into"]); !IsConsolidated(stored) || !slices.Contains(into, semantic.ID) {
+			t.Errorf("source %s consolidated into %v after the record rollback, want %s", source.ID, into, semantic.ID)
+		}
+	}
+
+	// Rolling back the summary itself restores its sources
+	if result, err = vj.RollbackConsolidation(ctx, semantic.ID); err != nil {
+		t.Fatalf("failed to roll back summary: %v", err)
+	}
+	if len(result.Restored) != len(sources) {
+		t.Errorf("summary rollback restored %v, want every source", result.Restored)
+	}
+}
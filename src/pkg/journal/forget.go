@@ -0,0 +1,170 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/JaimeStill/persistent-context/pkg/vectordb"
+)
+
+// DeleteMemory permanently removes a memory of any type together with its chunks, stored
+// versions and every association referencing it, and prunes its ID from the AssociationIDs of
+// neighboring memories. The returned error wraps vectordb.ErrMemoryNotFound when the
+// memory does not exist.
+func (vj *VectorJournal) DeleteMemory(ctx context.Context, id string) (*models.ForgetResult, error) {
+	entry, err := vj.removeMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ForgetResult{Deleted: []string{id}}
+
+	if err := vj.deleteChunks(ctx, entry); err != nil {
+		return result, err
+	}
+
+	// Earlier revisions may hold the very content being removed
+	if err := vj.vectorDB.Versions().DeleteByMemoryID(ctx, id); err != nil {
+		return result, fmt.Errorf("failed to delete versions of memory %s: %w", id, err)
+	}
+
+	removed, err := vj.associations.RemoveMemory(ctx, id)
+	result.AssociationsRemoved = len(removed)
+	if err != nil {
+		return result, err
+	}
+
+	// Neighbors come from both the tracked associations and the memory's own references
+	neighbors := append([]string{}, entry.AssociationIDs...)
+	for _, association := range removed {
+		if association.SourceID == id {
+			neighbors = append(neighbors, association.TargetID)
+		} else {
+			neighbors = append(neighbors, association.SourceID)
+		}
+	}
+	sort.Strings(neighbors)
+
+	for _, neighborID := range slices.Compact(neighbors) {
+		updated, err := vj.pruneAssociationID(ctx, neighborID, id)
+		if err != nil {
+			return result, err
+		}
+		if updated {
+			result.NeighborsUpdated++
+		}
+	}
+
+	slog.Info("Memory deleted",
+		"id", id,
+		"type", entry.Type,
+		"associations_removed", result.AssociationsRemoved,
+		"neighbors_updated", result.NeighborsUpdated)
+
+	return result, nil
+}
+
+// ForgetMemories deletes each memory with DeleteMemory. IDs that do not exist are
+// reported in NotFound rather than failing the whole request.
+func (vj *VectorJournal) ForgetMemories(ctx context.Context, ids []string) (*models.ForgetResult, error) {
+	result := &models.ForgetResult{Deleted: []string{}}
+
+	for _, id := range ids {
+		deleted, err := vj.DeleteMemory(ctx, id)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+		if deleted != nil {
+			result.Deleted = append(result.Deleted, deleted.Deleted...)
+			result.AssociationsRemoved += deleted.AssociationsRemoved
+			result.NeighborsUpdated += deleted.NeighborsUpdated
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to forget memory %s: %w", id, err)
+		}
+	}
+
+	return result, nil
+}
+
+// removeMemory deletes a memory's own entry under its lock, so a concurrent update
+// cannot write it back, and returns the entry as it was
+func (vj *VectorJournal) removeMemory(ctx context.Context, id string) (*models.MemoryEntry, error) {
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := vj.vectorDB.Memories().Delete(ctx, entry.Type, []string{id}); err != nil {
+		return nil, fmt.Errorf("failed to delete memory %s: %w", id, err)
+	}
+	return entry, nil
+}
+
+// locateMemory finds a memory by ID across every configured memory type
+func (vj *VectorJournal) locateMemory(ctx context.Context, id string) (*models.MemoryEntry, error) {
+	for _, memType := range vj.memoryTypes() {
+		entry, err := vj.vectorDB.Memories().Retrieve(ctx, memType, id)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve memory %s: %w", id, err)
+		}
+		return entry, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", vectordb.ErrMemoryNotFound, id)
+}
+
+// memoryTypes returns the configured memory types in a stable order
+func (vj *VectorJournal) memoryTypes() []models.MemoryType {
+	types := make([]models.MemoryType, 0, len(vj.vectorDBConfig.MemoryCollections))
+	for memType := range vj.vectorDBConfig.MemoryCollections {
+		types = append(types, models.MemoryType(memType))
+	}
+
+	// Episodic memories are the most common lookup, so check them first
+	sort.Slice(types, func(i, j int) bool {
+		if (types[i] == models.TypeEpisodic) != (types[j] == models.TypeEpisodic) {
+			return types[i] == models.TypeEpisodic
+		}
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// pruneAssociationID removes a deleted memory's ID from a neighbor's AssociationIDs.
+// It reports whether the neighbor was changed; missing neighbors are skipped.
+func (vj *VectorJournal) pruneAssociationID(ctx context.Context, neighborID, deletedID string) (bool, error) {
+	defer vj.locks.lock(neighborID)()
+
+	neighbor, err := vj.locateMemory(ctx, neighborID)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	pruned := slices.DeleteFunc(slices.Clone(neighbor.AssociationIDs), func(associated string) bool {
+		return associated == deletedID
+	})
+	if len(pruned) == len(neighbor.AssociationIDs) {
+		return false, nil
+	}
+
+	neighbor.AssociationIDs = pruned
+	if err := vj.vectorDB.Memories().Store(ctx, neighbor); err != nil {
+		return false, fmt.Errorf("failed to update associations of memory %s: %w", neighborID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/JaimeStill/persistent-context/pkg/llm"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// charsPerToken approximates how many characters of English text make up one model token
+const charsPerToken = 4
+
+// consolidationPromptTokens reserves room in each batch for the prompt instructions and the response
+const consolidationPromptTokens = 1024
+
+// minBatchTokens keeps a usable batch even when the configured budget is smaller than the prompt overhead
+const minBatchTokens = 256
+
+// ConsolidateOptions configures a consolidation run
+type ConsolidateOptions struct {
+	BatchSize int // Maximum memories per consolidation prompt (0 bounds batches by the token budget only)
+}
+
+// consolidationPlan records how a group was split across consolidation prompts
+type consolidationPlan struct {
+	batches    int  // Batches the sources were consolidated in
+	levels     int  // Reduce passes needed to merge the batch summaries
+	extractive bool // Some summary fell back to extraction because the model failed
+}
+
+// estimateTokens approximates the number of model tokens in text
+func estimateTokens(text string) int {
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// batchTokens returns the token budget for the memories in one consolidation prompt.
+// It is derived from the memory context window and safety margin, capped by the
+// journal's consolidation_batch_tokens setting.
+func (vj *VectorJournal) batchTokens() int {
+	budget := int(float64(vj.memoryConfig.MaxTokens) * vj.memoryConfig.SafetyMargin)
+	if limit := vj.config.ConsolidationBatchTokens; limit > 0 && (budget <= 0 || limit < budget) {
+		budget = limit
+	}
+
+	return max(budget-consolidationPromptTokens, minBatchTokens)
+}
+
+// consolidateHierarchically extracts knowledge from memories that may not fit in one prompt.
+// The memories are split into token-bounded batches and each batch is consolidated on its
+// own (map); the batch summaries are then consolidated, in further batches if needed, until
+// a single summary remains (reduce). Concepts, patterns and insights from every batch are
+// kept, and their evidence still refers to the original memories.
+func (vj *VectorJournal) consolidateHierarchically(ctx context.Context, memories []*models.MemoryEntry, opts ConsolidateOptions) (*models.Knowledge, consolidationPlan, error) {
+	budget := vj.batchTokens()
+	batches := splitBatches(memories, func(memory *models.MemoryEntry) int {
+		return estimateTokens(memory.Content)
+	}, budget, opts.BatchSize, 1)
+	plan := consolidationPlan{batches: len(batches)}
+
+	if len(batches) == 1 {
+		knowledge, err := vj.extractKnowledge(ctx, memories, &plan)
+		return knowledge, plan, err
+	}
+
+	slog.Info("Consolidating memories hierarchically",
+		"source_count", len(memories),
+		"batches", len(batches),
+		"batch_tokens", budget)
+
+	merged := &models.Knowledge{}
+	summaries := make([]string, 0, len(batches))
+	for i, batch := range batches {
+		knowledge, err := vj.extractKnowledge(ctx, batch, &plan)
+		if err != nil {
+			return nil, plan, fmt.Errorf("failed to consolidate batch %d of %d: %w", i+1, len(batches), err)
+		}
+
+		summaries = append(summaries, knowledge.Summary)
+		merged.Concepts = append(merged.Concepts, knowledge.Concepts...)
+		merged.Patterns = append(merged.Patterns, knowledge.Patterns...)
+		merged.Insights = append(merged.Insights, knowledge.Insights...)
+	}
+
+	summary, err := vj.reduceSummaries(ctx, summaries, budget, opts.BatchSize, &plan)
+	if err != nil {
+		return nil, plan, err
+	}
+	merged.Summary = summary
+
+	return merged, plan, nil
+}
+
+// reduceSummaries consolidates batch summaries until one remains, recording the number
+// of reduce passes in plan. Each pass batches at least two summaries so it always shrinks.
+func (vj *VectorJournal) reduceSummaries(ctx context.Context, summaries []string, budget, batchSize int, plan *consolidationPlan) (string, error) {
+	for len(summaries) > 1 {
+		plan.levels++
+		batches := splitBatches(summaries, estimateTokens, budget, batchSize, 2)
+
+		reduced := make([]string, 0, len(batches))
+		for i, batch := range batches {
+			if len(batch) == 1 {
+				reduced = append(reduced, batch[0])
+				continue
+			}
+
+			summary, err := vj.summarize(ctx, batch, plan)
+			if err != nil {
+				return "", fmt.Errorf("failed to merge summaries (pass %d, batch %d of %d): %w", plan.levels, i+1, len(batches), err)
+			}
+			reduced = append(reduced, summary)
+		}
+
+		summaries = reduced
+	}
+
+	return summaries[0], nil
+}
+
+// extractKnowledge asks for structured knowledge from one batch of memories, falling back
+// to a plain summary if the model cannot produce it
+func (vj *VectorJournal) extractKnowledge(ctx context.Context, memories []*models.MemoryEntry, plan *consolidationPlan) (*models.Knowledge, error) {
+	knowledge, err := vj.llmClient.ExtractKnowledge(ctx, memories)
+	if err == nil {
+		return knowledge, nil
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to consolidate memories: %w", err)
+	}
+	slog.Warn("Structured consolidation failed, falling back to a summary", "error", err)
+
+	// Extract content for consolidation
+	memoryTexts := make([]string, len(memories))
+	for i, mem := range memories {
+		memoryTexts[i] = mem.Content
+	}
+
+	summary, err := vj.summarize(ctx, memoryTexts, plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consolidate memories: %w", err)
+	}
+	return &models.Knowledge{Summary: summary}, nil
+}
+
+// summarize asks the model for a free-text summary. When the model fails and the
+// extractive fallback is enabled, an extractive summary is used instead and plan is
+// marked so the result is not mistaken for a real consolidation.
+func (vj *VectorJournal) summarize(ctx context.Context, texts []string, plan *consolidationPlan) (string, error) {
+	summary, err := vj.llmClient.ConsolidateMemories(ctx, texts)
+	if err == nil {
+		return summary, nil
+	}
+	if ctx.Err() != nil || !vj.config.ExtractiveFallback {
+		return "", err
+	}
+	slog.Warn("Generative consolidation failed, falling back to an extractive summary", "error", err)
+
+	if summary = llm.ExtractiveSummary(texts); summary == "" {
+		return "", err
+	}
+	plan.extractive = true
+	return summary, nil
+}
+
+// splitBatches groups items in order so each batch stays within the token budget and,
+// when maxSize is positive, holds at most maxSize items. A batch is only closed once it
+// has minSize items, so an item larger than the budget still forms a batch of its own.
+func splitBatches[T any](items []T, tokens func(T) int, budget, maxSize, minSize int) [][]T {
+	var batches [][]T
+	var current []T
+	used := 0
+
+	for _, item := range items {
+		cost := tokens(item)
+		full := used+cost > budget || (maxSize > 0 && len(current) >= maxSize)
+		if len(current) >= minSize && full {
+			batches = append(batches, current)
+			current, used = nil, 0
+		}
+
+		current = append(current, item)
+		used += cost
+	}
+
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
@@ -0,0 +1,140 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/JaimeStill/persistent-context/pkg/llm"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// unreachableModel embeds normally but fails every generative request
+type unreachableModel struct {
+	llm.LLM
+}
+
+var errModelDown = errors.New("model unreachable")
+
+func (unreachableModel) ConsolidateMemories(ctx context.Context, memories []string) (string, error) {
+	return "", errModelDown
+}
+
+func (unreachableModel) ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	return nil, errModelDown
+}
+
+// captureSources captures a few episodes for consolidation
+func captureSources(t *testing.T, vj *VectorJournal) []*models.MemoryEntry {
+	t.Helper()
+
+	var sources []*models.MemoryEntry
+	for _, content := range []string{
+		"The deploy failed because the migration timed out.",
+		"We raised the migration timeout and the deploy succeeded.",
+	} {
+		entry, err := vj.CaptureContext(context.Background(), "test", content, nil)
+		if err != nil {
+			t.Fatalf("failed to capture memory: %v", err)
+		}
+		sources = append(sources, entry)
+	}
+	return sources
+}
+
+func TestConsolidationFailsWithoutExtractiveFallback(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	vj.llmClient = unreachableModel{vj.llmClient}
+	sources := captureSources(t, vj)
+
+	if _, err := vj.ConsolidateMemories(context.Background(), sources, ConsolidateOptions{}); !errors.Is(err, errModelDown) {
+		t.Fatalf("consolidation returned %v, want the model error", err)
+	}
+}
+
+func TestExtractiveFallbackLeavesSourcesUnconsolidated(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	vj.llmClient = unreachableModel{vj.llmClient}
+	vj.config.ExtractiveFallback = true
+	sources := captureSources(t, vj)
+
+	semantic, err := vj.ConsolidateMemories(context.Background(), sources, ConsolidateOptions{})
+	if err != nil {
+		t.Fatalf("failed to consolidate: %v", err)
+	}
+	if !IsExtractive(semantic) {
+		t.Error("fallback summary is not tagged extractive")
+	}
+
+	for _, source := range sources {
+		stored, err := vj.locateMemory(context.Background(), source.ID)
+		if err != nil {
+			t.Fatalf("failed to reload source: %v", err)
+		}
+		if IsConsolidated(stored) {
+			t.Errorf("source %s marked consolidated by an extractive summary", source.ID)
+		}
+		if stored.Strength != source.Strength {
+			t.Errorf("source %s strength changed from %v to %v", source.ID, source.Strength, stored.Strength)
+		}
+	}
+}
+
+// semanticIDs lists the IDs of every stored semantic memory
+func semanticIDs(t *testing.T, vj *VectorJournal) []string {
+	t.Helper()
+
+	var ids []string
+	err := vj.scanMemories(context.Background(), models.TypeSemantic, func(entry *models.MemoryEntry) {
+		ids = append(ids, entry.ID)
+	})
+	if err != nil {
+		t.Fatalf("failed to scan semantic memories: %v", err)
+	}
+	return ids
+}
+
+func TestExtractiveFallbackReplacesEarlierSummary(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	model := vj.llmClient
+	vj.llmClient = unreachableModel{model}
+	vj.config.ExtractiveFallback = true
+	sources := captureSources(t, vj)
+
+	var summary *models.MemoryEntry
+	for run := 0; run < 2; run++ {
+		var err error
+		if summary, err = vj.ConsolidateMemories(context.Background(), sources, ConsolidateOptions{}); err != nil {
+			t.Fatalf("failed to consolidate on run %d: %v", run+1, err)
+		}
+	}
+
+	if ids := semanticIDs(t, vj); len(ids) != 1 || ids[0] != summary.ID {
+		t.Fatalf("semantic memories after two fallback runs are %v, want only %s", ids, summary.ID)
+	}
+
+	// Once the model is back, the real consolidation takes the summary's place
+	vj.llmClient = model
+	semantic, err := vj.ConsolidateMemories(context.Background(), sources, ConsolidateOptions{})
+	if err != nil {
+		t.Fatalf("failed to consolidate: %v", err)
+	}
+	if IsExtractive(semantic) {
+		t.Fatal("consolidation with a working model is tagged extractive")
+	}
+
+	for _, id := range semanticIDs(t, vj) {
+		if id == summary.ID {
+			t.Errorf("extractive summary %s kept after a real consolidation", id)
+		}
+	}
+	for _, source := range sources {
+		stored, err := vj.locateMemory(context.Background(), source.ID)
+		if err != nil {
+			t.Fatalf("failed to reload source: %v", err)
+		}
+		if _, exists := stored.Metadata["extractive_into"]; exists {
+			t.Errorf("consolidated source %s still names an extractive summary", source.ID)
+		}
+	}
+}
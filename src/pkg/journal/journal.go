@@ -12,20 +12,68 @@ import (
 
 // Journal defines the interface for LLM memory journal storage and retrieval operations
 type Journal interface {
+	// Initialize restores persisted state such as the association graph
+	Initialize(ctx context.Context) error
+	
+	// Start launches background workers bound to the lifecycle context
+	Start(ctx context.Context) error
+	
+	// Stop drains pending background work, cancelling it if ctx expires first
+	Stop(ctx context.Context) error
+	
 	// CaptureContext captures and stores a new memory from context
 	CaptureContext(ctx context.Context, source string, content string, metadata map[string]any) (*models.MemoryEntry, error)
 	
+	// CaptureBatch captures several memories with batched embedding, returning them in input order
+	CaptureBatch(ctx context.Context, captures []*models.CaptureMemoryRequest) ([]*models.MemoryEntry, error)
+	
 	// GetMemories retrieves memories with pagination
 	GetMemories(ctx context.Context, limit uint32) ([]*models.MemoryEntry, error)
 	
-	// GetMemoryByID retrieves a specific memory by ID
+	// ListMemories retrieves recent memories matching an optional filter
+	ListMemories(ctx context.Context, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error)
+	
+	// GetMemoryByID retrieves a specific memory by ID from any memory type
 	GetMemoryByID(ctx context.Context, id string) (*models.MemoryEntry, error)
 	
+	// GetMemoryAssociations returns the associations referencing a memory
+	GetMemoryAssociations(ctx context.Context, id string) ([]*models.MemoryAssociation, error)
+	
+	// GetSourceMemories returns the memories a consolidated memory was created from
+	GetSourceMemories(ctx context.Context, entry *models.MemoryEntry) ([]*models.MemoryEntry, error)
+	
 	// QuerySimilarMemories finds similar memories using vector similarity
 	QuerySimilarMemories(ctx context.Context, content string, memType models.MemoryType, limit uint64) ([]*models.MemoryEntry, error)
 	
-	// ConsolidateMemories consolidates episodic memories into semantic knowledge
-	ConsolidateMemories(ctx context.Context, memories []*models.MemoryEntry) error
+	// SearchMemories finds memories using vector, keyword or hybrid search
+	SearchMemories(ctx context.Context, query string, options SearchOptions) ([]*models.MemoryEntry, error)
+	
+	// UpdateMemory edits a memory's content or metadata, keeping the prior revision
+	UpdateMemory(ctx context.Context, id string, update models.MemoryUpdate) (*models.MemoryEntry, error)
+	
+	// ListMemoryVersions returns every revision of a memory, ending with the current one
+	ListMemoryVersions(ctx context.Context, id string) ([]*models.MemoryVersion, error)
+	
+	// RestoreMemoryVersion makes an earlier revision of a memory current again
+	RestoreMemoryVersion(ctx context.Context, id string, version int, editor string) (*models.MemoryEntry, error)
+	
+	// DeleteMemory removes a memory and cascades to its associations and neighbors
+	DeleteMemory(ctx context.Context, id string) (*models.ForgetResult, error)
+	
+	// ForgetMemories deletes several memories, reporting IDs that were not found
+	ForgetMemories(ctx context.Context, ids []string) (*models.ForgetResult, error)
+	
+	// ApplyRetention enforces the retention, strength and size policies; dryRun only reports what would be removed
+	ApplyRetention(ctx context.Context, dryRun bool) (*models.RetentionReport, error)
+	
+	// ConsolidateMemories consolidates episodic memories into semantic knowledge, returning the semantic memory
+	ConsolidateMemories(ctx context.Context, memories []*models.MemoryEntry, opts ConsolidateOptions) (*models.MemoryEntry, error)
+	
+	// GetLineage walks a memory back through the consolidations it was derived from
+	GetLineage(ctx context.Context, id string) (*models.LineageNode, error)
+	
+	// RollbackConsolidation deletes a consolidated memory and returns its sources to unconsolidated
+	RollbackConsolidation(ctx context.Context, id string) (*models.RollbackResult, error)
 	
 	// GetMemoryStats returns statistics about stored memories
 	GetMemoryStats(ctx context.Context) (map[string]any, error)
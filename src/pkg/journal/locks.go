@@ -0,0 +1,26 @@
+package journal
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// memoryLockStripes is the number of mutexes memory IDs are hashed across
+const memoryLockStripes = 64
+
+// memoryLocks serializes read-modify-write cycles on individual memories. IDs are
+// striped across a fixed set of mutexes, so unrelated memories occasionally share
+// one; callers must therefore never hold more than one memory lock at a time.
+type memoryLocks struct {
+	stripes [memoryLockStripes]sync.Mutex
+}
+
+// lock acquires the mutex guarding id and returns the function that releases it
+func (ml *memoryLocks) lock(id string) func() {
+	hash := fnv.New32a()
+	hash.Write([]byte(id))
+
+	mu := &ml.stripes[hash.Sum32()%memoryLockStripes]
+	mu.Lock()
+	return mu.Unlock
+}
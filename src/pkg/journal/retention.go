@@ -0,0 +1,319 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// retentionScheduler periodically enforces the journal's retention policies
+type retentionScheduler struct {
+	interval time.Duration
+	run      func(ctx context.Context) (*models.RetentionReport, error)
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	done     chan struct{}
+	last     *models.RetentionReport
+	lastErr  error
+	next     time.Time
+}
+
+// newRetentionScheduler creates a scheduler that calls run every interval; a zero interval disables it
+func newRetentionScheduler(interval time.Duration, run func(ctx context.Context) (*models.RetentionReport, error)) *retentionScheduler {
+	return &retentionScheduler{
+		interval: interval,
+		run:      run,
+	}
+}
+
+// Start launches the schedule loop; it stops when ctx is cancelled or Stop is called
+func (rs *retentionScheduler) Start(ctx context.Context) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.interval <= 0 || rs.cancel != nil {
+		return
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	rs.cancel = cancel
+	rs.done = make(chan struct{})
+	rs.next = time.Now().Add(rs.interval)
+
+	go rs.loop(loopCtx, rs.done)
+
+	slog.Info("Retention scheduler started", "interval", rs.interval)
+}
+
+// Stop cancels the schedule loop and waits for an in-progress run to return
+func (rs *retentionScheduler) Stop(ctx context.Context) error {
+	rs.mu.Lock()
+	cancel, done := rs.cancel, rs.done
+	rs.cancel, rs.done = nil, nil
+	rs.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("retention run did not stop: %w", ctx.Err())
+	}
+}
+
+// loop runs retention on every tick until ctx is cancelled
+func (rs *retentionScheduler) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(rs.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := rs.run(ctx)
+			if err != nil {
+				slog.Error("Scheduled retention failed", "error", err)
+			}
+
+			rs.mu.Lock()
+			if report != nil {
+				rs.last = report
+			}
+			rs.lastErr = err
+			rs.next = time.Now().Add(rs.interval)
+			rs.mu.Unlock()
+		}
+	}
+}
+
+// Stats reports the schedule and the outcome of the last scheduled run
+func (rs *retentionScheduler) Stats() map[string]any {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	stats := map[string]any{
+		"enabled":  rs.interval > 0,
+		"interval": rs.interval.String(),
+	}
+	if rs.cancel != nil {
+		stats["next_run"] = rs.next
+	}
+	if rs.last != nil {
+		stats["last_run"] = rs.last.CompletedAt
+		stats["last_scanned"] = rs.last.Scanned
+		stats["last_forgotten"] = 0
+		if rs.last.Result != nil {
+			stats["last_forgotten"] = len(rs.last.Result.Deleted)
+		}
+	}
+	if rs.lastErr != nil {
+		stats["last_error"] = rs.lastErr.Error()
+	}
+	return stats
+}
+
+// ApplyRetention enforces the retention policies: episodic memories older than
+// RetentionDays expire, episodic memories at least StrengthMinAgeDays old whose decayed
+// strength is below a non-zero StrengthThreshold are dropped, and if more than
+// MaxMemorySize remain the lowest CompositeScore memories are evicted. With dryRun set
+// the candidates are reported but kept. Deletes go through ForgetMemories and so
+// cascade to associations.
+func (vj *VectorJournal) ApplyRetention(ctx context.Context, dryRun bool) (*models.RetentionReport, error) {
+	// Only one run may delete at a time; previews don't need to wait
+	if !dryRun {
+		vj.retentionMu.Lock()
+		defer vj.retentionMu.Unlock()
+	}
+
+	report := &models.RetentionReport{
+		DryRun:     dryRun,
+		Candidates: []*models.RetentionCandidate{},
+		StartedAt:  time.Now(),
+	}
+
+	var cutoff time.Time
+	if vj.config.RetentionDays > 0 {
+		cutoff = report.StartedAt.AddDate(0, 0, -vj.config.RetentionDays)
+	}
+	weakCutoff := report.StartedAt.AddDate(0, 0, -vj.config.StrengthMinAgeDays)
+
+	survivors := 0
+	for _, memType := range vj.memoryTypes() {
+		err := vj.scanMemories(ctx, memType, func(entry *models.MemoryEntry) {
+			report.Scanned++
+			entry.Score = vj.scorer.ScoreMemory(entry)
+
+			switch {
+			case memType == models.TypeEpisodic && !cutoff.IsZero() && entry.CreatedAt.Before(cutoff):
+				report.Candidates = append(report.Candidates, retentionCandidate(entry, models.RetentionExpired))
+			case memType == models.TypeEpisodic && vj.isWeak(entry, weakCutoff):
+				report.Candidates = append(report.Candidates, retentionCandidate(entry, models.RetentionWeak))
+			default:
+				survivors++
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if excess := survivors - int(vj.config.MaxMemorySize); excess > 0 {
+		if err := vj.selectOverCapacity(ctx, report, excess); err != nil {
+			return nil, err
+		}
+	}
+
+	if !dryRun && len(report.Candidates) > 0 {
+		ids := make([]string, len(report.Candidates))
+		for i, candidate := range report.Candidates {
+			ids[i] = candidate.ID
+		}
+
+		result, err := vj.ForgetMemories(ctx, ids)
+		report.Result = result
+		if err != nil {
+			return report, fmt.Errorf("failed to apply retention: %w", err)
+		}
+	}
+
+	report.CompletedAt = time.Now()
+
+	slog.Info("Retention applied",
+		"dry_run", dryRun,
+		"scanned", report.Scanned,
+		"candidates", len(report.Candidates),
+		"duration", report.CompletedAt.Sub(report.StartedAt))
+
+	return report, nil
+}
+
+// isWeak reports whether an episodic memory's decayed strength fell below the strength
+// threshold. The rule is off while StrengthThreshold is zero, and it never removes a
+// memory created after cutoff, so new memories get StrengthMinAgeDays to be used.
+func (vj *VectorJournal) isWeak(entry *models.MemoryEntry, cutoff time.Time) bool {
+	if vj.config.StrengthThreshold <= 0 {
+		return false
+	}
+	if !entry.CreatedAt.Before(cutoff) {
+		return false
+	}
+	return decayedStrength(entry) < float64(vj.config.StrengthThreshold)
+}
+
+// selectOverCapacity adds the excess lowest CompositeScore memories not already selected
+// to the report. Each round rescans every type but holds at most BatchSize memories,
+// so memory use stays bounded however large the collections are.
+func (vj *VectorJournal) selectOverCapacity(ctx context.Context, report *models.RetentionReport, excess int) error {
+	selected := make(map[string]bool, len(report.Candidates)+excess)
+	for _, candidate := range report.Candidates {
+		selected[candidate.ID] = true
+	}
+
+	for excess > 0 {
+		round := min(excess, int(vj.config.BatchSize))
+		lowest := make([]*models.MemoryEntry, 0, round+1)
+
+		for _, memType := range vj.memoryTypes() {
+			err := vj.scanMemories(ctx, memType, func(entry *models.MemoryEntry) {
+				if selected[entry.ID] {
+					return
+				}
+				entry.Score = vj.scorer.ScoreMemory(entry)
+				lowest = insertByScore(lowest, entry, round)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(lowest) == 0 {
+			return nil
+		}
+		for _, entry := range lowest {
+			selected[entry.ID] = true
+			report.Candidates = append(report.Candidates, retentionCandidate(entry, models.RetentionOverCapacity))
+		}
+		excess -= len(lowest)
+	}
+
+	return nil
+}
+
+// insertByScore inserts entry into lowest, kept in ascending CompositeScore order, and
+// trims it to limit entries
+func insertByScore(lowest []*models.MemoryEntry, entry *models.MemoryEntry, limit int) []*models.MemoryEntry {
+	at := sort.Search(len(lowest), func(i int) bool {
+		if lowest[i].Score.CompositeScore == entry.Score.CompositeScore {
+			return lowest[i].ID > entry.ID
+		}
+		return lowest[i].Score.CompositeScore > entry.Score.CompositeScore
+	})
+	if at >= limit {
+		return lowest
+	}
+
+	lowest = slices.Insert(lowest, at, entry)
+	if len(lowest) > limit {
+		lowest = lowest[:limit]
+	}
+	return lowest
+}
+
+// scanMemories pages through every memory of a type, calling visit for each
+func (vj *VectorJournal) scanMemories(ctx context.Context, memType models.MemoryType, visit func(entry *models.MemoryEntry)) error {
+	seen := make(map[string]bool)
+	cursor := ""
+
+	for {
+		page, nextCursor, err := vj.vectorDB.Memories().GetAll(ctx, memType, cursor, vj.config.BatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to scan %s memories: %w", memType, err)
+		}
+
+		// Some stores return the cursor memory again as the first item of the next page
+		added := 0
+		for _, entry := range page {
+			if seen[entry.ID] {
+				continue
+			}
+			seen[entry.ID] = true
+			visit(entry)
+			added++
+		}
+
+		if nextCursor == "" || nextCursor == cursor || added == 0 {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// decayedStrength is a memory's strength after time decay since its last access
+func decayedStrength(entry *models.MemoryEntry) float64 {
+	return float64(entry.Strength) * entry.Score.DecayFactor
+}
+
+// retentionCandidate describes a scored memory selected for removal
+func retentionCandidate(entry *models.MemoryEntry, reason models.RetentionReason) *models.RetentionCandidate {
+	return &models.RetentionCandidate{
+		ID:              entry.ID,
+		Type:            entry.Type,
+		Reason:          reason,
+		CreatedAt:       entry.CreatedAt,
+		DecayedStrength: decayedStrength(entry),
+		CompositeScore:  entry.Score.CompositeScore,
+	}
+}
@@ -0,0 +1,131 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// storeAged stores a memory of the given type last accessed and created age ago
+func storeAged(t *testing.T, vj *VectorJournal, memType models.MemoryType, content string, age time.Duration, strength float32) *models.MemoryEntry {
+	t.Helper()
+	ctx := context.Background()
+
+	entry, err := vj.CaptureContext(ctx, "retention", content, nil)
+	if err != nil {
+		t.Fatalf("failed to capture memory: %v", err)
+	}
+	if memType != models.TypeEpisodic {
+		if err := vj.vectorDB.Memories().Delete(ctx, models.TypeEpisodic, []string{entry.ID}); err != nil {
+			t.Fatalf("failed to move memory: %v", err)
+		}
+		entry.Type = memType
+	}
+
+	entry.CreatedAt = time.Now().Add(-age)
+	entry.AccessedAt = entry.CreatedAt
+	entry.Strength = strength
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		t.Fatalf("failed to store memory: %v", err)
+	}
+	return entry
+}
+
+// reasons maps each retention candidate to the reason it was selected
+func reasons(report *models.RetentionReport) map[string]models.RetentionReason {
+	selected := make(map[string]models.RetentionReason, len(report.Candidates))
+	for _, candidate := range report.Candidates {
+		selected[candidate.ID] = candidate.Reason
+	}
+	return selected
+}
+
+func TestRetentionStrengthRule(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	vj.config.RetentionDays = 30
+	vj.config.StrengthThreshold = 0.1
+	vj.config.StrengthMinAgeDays = 7
+
+	// The stress journal doesn't decay, so stored strength decides which memories are weak
+	young := storeAged(t, vj, models.TypeEpisodic, "a weak but recent episode", 3*24*time.Hour, 0.05)
+	weak := storeAged(t, vj, models.TypeEpisodic, "a weak episode", 10*24*time.Hour, 0.05)
+	strong := storeAged(t, vj, models.TypeEpisodic, "a strong episode", 10*24*time.Hour, 1)
+	semantic := storeAged(t, vj, models.TypeSemantic, "an old weak semantic fact", 60*24*time.Hour, 0.05)
+	old := storeAged(t, vj, models.TypeEpisodic, "an old episode", 40*24*time.Hour, 1)
+
+	report, err := vj.ApplyRetention(context.Background(), true)
+	if err != nil {
+		t.Fatalf("failed to apply retention: %v", err)
+	}
+
+	selected := reasons(report)
+	for _, kept := range []*models.MemoryEntry{young, strong, semantic} {
+		if reason, exists := selected[kept.ID]; exists {
+			t.Errorf("memory %q selected as %s", kept.Content, reason)
+		}
+	}
+	if selected[weak.ID] != models.RetentionWeak {
+		t.Errorf("weak episode selected as %q, want %q", selected[weak.ID], models.RetentionWeak)
+	}
+	if selected[old.ID] != models.RetentionExpired {
+		t.Errorf("old episode selected as %q, want %q", selected[old.ID], models.RetentionExpired)
+	}
+
+	// Without a threshold nothing is dropped for strength, even without an age limit
+	vj.config.RetentionDays = 0
+	vj.config.StrengthThreshold = 0
+	if report, err = vj.ApplyRetention(context.Background(), true); err != nil {
+		t.Fatalf("failed to apply retention: %v", err)
+	}
+	if len(report.Candidates) != 0 {
+		t.Errorf("selected %d memories with retention disabled", len(report.Candidates))
+	}
+}
+
+func TestRetentionOverCapacity(t *testing.T) {
+	vj, db := newStressJournal(t)
+	vj.config.BatchSize = 3 // Evict over several rounds
+	vj.config.MaxMemorySize = 4
+
+	for i := 0; i < 12; i++ {
+		storeAged(t, vj, models.TypeEpisodic, fmt.Sprintf("capacity note %d", i), time.Duration(12-i)*24*time.Hour, 1)
+	}
+
+	report, err := vj.ApplyRetention(context.Background(), false)
+	if err != nil {
+		t.Fatalf("failed to apply retention: %v", err)
+	}
+
+	selected := reasons(report)
+	if len(selected) != 8 {
+		t.Errorf("evicted %d memories, want 8", len(selected))
+	}
+
+	// Every evicted memory scored no higher than every memory kept
+	highest := 0.0
+	for _, candidate := range report.Candidates {
+		if candidate.Reason != models.RetentionOverCapacity {
+			t.Errorf("memory %s selected as %q, want %q", candidate.ID, candidate.Reason, models.RetentionOverCapacity)
+		}
+		highest = max(highest, candidate.CompositeScore)
+	}
+	err = vj.scanMemories(context.Background(), models.TypeEpisodic, func(entry *models.MemoryEntry) {
+		if score := vj.scorer.ScoreMemory(entry).CompositeScore; score < highest {
+			t.Errorf("kept memory %s scores %v, below evicted score %v", entry.ID, score, highest)
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to scan memories: %v", err)
+	}
+
+	count, err := db.Memories().Count(context.Background(), models.TypeEpisodic)
+	if err != nil {
+		t.Fatalf("failed to count memories: %v", err)
+	}
+	if count != vj.config.MaxMemorySize {
+		t.Errorf("%d memories remain, want %d", count, vj.config.MaxMemorySize)
+	}
+}
@@ -29,7 +29,7 @@ func (ms *MemoryScorer) ScoreMemory(memory *models.MemoryEntry) models.MemorySco
 	accessFreq := 1
 	if memory.Score.AccessFrequency > 0 {
 		accessFreq = memory.Score.AccessFrequency
-	} else if freq, ok := memory.Metadata["access_count"].(int); ok {
+	} else if freq, ok := accessCountFromMetadata(memory.Metadata); ok {
 		accessFreq = freq
 	}
 
@@ -126,6 +126,49 @@ func (ms *MemoryScorer) calculateCompositeScore(baseImportance, decayFactor floa
 	return compositeScore
 }
 
+// associationBoostWeight scales how much a memory's association strengths lift its search rank
+const associationBoostWeight = 0.1
+
+// RankSearchResult scores a search hit and combines its retrieval relevance with the
+// memory's composite importance and association strength into a final rank
+func (ms *MemoryScorer) RankSearchResult(memory *models.MemoryEntry, relevance float64, associations []*models.MemoryAssociation) models.RankExplanation {
+	memory.Score = ms.ScoreMemory(memory)
+
+	totalStrength := 0.0
+	for _, association := range associations {
+		totalStrength += association.Strength
+	}
+	associationBoost := associationBoostWeight * math.Log(1+totalStrength)
+
+	finalScore := relevance * (1 + memory.Score.CompositeScore) * (1 + associationBoost)
+
+	return models.RankExplanation{
+		Similarity:        memory.Similarity,
+		Relevance:         relevance,
+		BaseImportance:    memory.Score.BaseImportance,
+		Decay:             memory.Score.DecayFactor,
+		AccessFrequency:   math.Log(1+float64(memory.Score.AccessFrequency)) * ms.config.AccessWeight,
+		StrengthComponent: memory.Score.RelevanceScore * ms.config.RelevanceWeight,
+		CompositeScore:    memory.Score.CompositeScore,
+		AssociationBoost:  associationBoost,
+		FinalScore:        finalScore,
+	}
+}
+
+// accessCountFromMetadata reads the access counter, which storage backends may return as any numeric type
+func accessCountFromMetadata(metadata map[string]any) (int, bool) {
+	switch v := metadata["access_count"].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
 // UpdateMemoryAccess updates access tracking and recalculates score when memory is used
 // Creates positive feedback loop where useful memories become more accessible
 func (ms *MemoryScorer) UpdateMemoryAccess(memory *models.MemoryEntry) {
@@ -134,7 +177,7 @@ func (ms *MemoryScorer) UpdateMemoryAccess(memory *models.MemoryEntry) {
 	// Increment access frequency counter
 	if memory.Score.AccessFrequency == 0 {
 		// Initialize from metadata if migrating from old system
-		if freq, ok := memory.Metadata["access_count"].(int); ok {
+		if freq, ok := accessCountFromMetadata(memory.Metadata); ok {
 			memory.Score.AccessFrequency = freq + 1
 		} else {
 			memory.Score.AccessFrequency = 1
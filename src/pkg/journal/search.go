@@ -0,0 +1,243 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// rrfK is the reciprocal rank fusion smoothing constant from Cormack et al.
+const rrfK = 60
+
+// searchCandidateMultiplier widens each retrieval before fusion and reranking so
+// documents just outside the limit can still surface in the final ranking
+const searchCandidateMultiplier = 3
+
+// SearchOptions configures a journal search
+type SearchOptions struct {
+	MemoryType  models.MemoryType             // Memory type to search, or models.TypeAll (defaults to episodic)
+	Mode        models.SearchMode             // Vector, keyword or hybrid (defaults to vector)
+	Limit       uint64                        // Maximum results (defaults to 10)
+	TypeWeights map[models.MemoryType]float64 // Per-type weights for cross-type search (unlisted types weigh 1.0, 0 excludes)
+	Explain     bool                          // Attach a rank breakdown to each result
+	Filter      *models.MemoryFilter          // Optional payload filter applied to every retrieval
+}
+
+// searchHit is a retrieved memory with its retrieval relevance before reranking
+type searchHit struct {
+	entry     *models.MemoryEntry
+	relevance float64
+}
+
+// rankedList is a ranked result list contributing to rank fusion with a weight
+type rankedList struct {
+	entries []*models.MemoryEntry
+	weight  float64
+}
+
+// SearchMemories finds memories matching the query using the requested search mode.
+// With models.TypeAll it searches every configured memory type in parallel. Results
+// are ranked by relevance combined with each memory's composite importance score. Long
+// episodic memories also match through their chunks and are returned once, as the memory.
+func (vj *VectorJournal) SearchMemories(ctx context.Context, query string, options SearchOptions) ([]*models.MemoryEntry, error) {
+	if options.MemoryType == "" {
+		options.MemoryType = models.TypeEpisodic
+	}
+	if options.Limit == 0 {
+		options.Limit = 10 // Default limit
+	}
+
+	switch options.Mode {
+	case "":
+		options.Mode = models.SearchModeVector
+	case models.SearchModeVector, models.SearchModeKeyword, models.SearchModeHybrid:
+	default:
+		return nil, fmt.Errorf("unsupported search mode: %s", options.Mode)
+	}
+
+	weights := map[models.MemoryType]float64{options.MemoryType: 1}
+	if options.MemoryType == models.TypeAll {
+		var err error
+		if weights, err = vj.typeWeights(options.TypeWeights); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := options.Filter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid memory filter: %w", err)
+	}
+
+	hits, err := vj.retrieve(ctx, query, options.Mode, weights, options.Limit*searchCandidateMultiplier, options.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return vj.rankHits(hits, options.Limit, options.Explain), nil
+}
+
+// typeWeights resolves the weight of every configured memory type, dropping excluded types
+func (vj *VectorJournal) typeWeights(overrides map[models.MemoryType]float64) (map[models.MemoryType]float64, error) {
+	weights := make(map[models.MemoryType]float64, len(vj.vectorDBConfig.MemoryCollections))
+	for memType := range vj.vectorDBConfig.MemoryCollections {
+		weight := 1.0
+		if w, exists := overrides[models.MemoryType(memType)]; exists {
+			weight = w
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("type weight for %s cannot be negative", memType)
+		}
+		if weight > 0 {
+			weights[models.MemoryType(memType)] = weight
+		}
+	}
+	return weights, nil
+}
+
+// retrieve fans the search out across the weighted memory types in parallel.
+// Vector hits keep their weighted similarity as relevance; keyword and hybrid
+// hits are merged by weighted rank fusion.
+func (vj *VectorJournal) retrieve(ctx context.Context, query string, mode models.SearchMode, weights map[models.MemoryType]float64, candidates uint64, filter *models.MemoryFilter) ([]searchHit, error) {
+	// Embed the query once and share it across all vector queries
+	var embedding []float32
+	if mode != models.SearchModeKeyword {
+		var err error
+		embedding, err = vj.llmClient.GenerateEmbedding(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate query embedding: %w", err)
+		}
+	}
+
+	type typeResult struct {
+		memType models.MemoryType
+		vector  []*models.MemoryEntry
+		keyword []*models.MemoryEntry
+		err     error
+	}
+
+	results := make(chan typeResult, len(weights))
+	var wg sync.WaitGroup
+
+	for memType := range weights {
+		wg.Add(1)
+		go func(memType models.MemoryType) {
+			defer wg.Done()
+
+			result := typeResult{memType: memType}
+			if mode != models.SearchModeKeyword {
+				result.vector, result.err = vj.vectorDB.Memories().Query(ctx, memType, embedding, candidates, filter)
+				if result.err != nil {
+					result.err = fmt.Errorf("failed to query %s memories: %w", memType, result.err)
+					results <- result
+					return
+				}
+				if memType == models.TypeEpisodic {
+					if result.vector, result.err = vj.withChunkMatches(ctx, result.vector, embedding, candidates, filter); result.err != nil {
+						results <- result
+						return
+					}
+				}
+			}
+			if mode != models.SearchModeVector {
+				result.keyword, result.err = vj.vectorDB.Memories().KeywordQuery(ctx, memType, query, candidates, filter)
+				if result.err != nil {
+					result.err = fmt.Errorf("failed to query %s keyword memories: %w", memType, result.err)
+				}
+			}
+			results <- result
+		}(memType)
+	}
+
+	wg.Wait()
+	close(results)
+
+	var lists []rankedList
+	var hits []searchHit
+	for result := range results {
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		weight := weights[result.memType]
+		switch mode {
+		case models.SearchModeVector:
+			for _, entry := range result.vector {
+				hits = append(hits, searchHit{entry: entry, relevance: entry.Similarity * weight})
+			}
+		case models.SearchModeKeyword:
+			lists = append(lists, rankedList{entries: result.keyword, weight: weight})
+		default:
+			lists = append(lists,
+				rankedList{entries: result.vector, weight: weight},
+				rankedList{entries: result.keyword, weight: weight})
+		}
+	}
+
+	if mode != models.SearchModeVector {
+		return fuseRankings(lists), nil
+	}
+	return hits, nil
+}
+
+// rankHits combines each hit's relevance with its importance score and returns the top results
+func (vj *VectorJournal) rankHits(hits []searchHit, limit uint64, explain bool) []*models.MemoryEntry {
+	for _, hit := range hits {
+		explanation := vj.scorer.RankSearchResult(hit.entry, hit.relevance, vj.associations.GetAssociationsForMemory(hit.entry.ID))
+		hit.entry.RankScore = explanation.FinalScore
+		if explain {
+			hit.entry.Explanation = &explanation
+		}
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].entry.RankScore == hits[j].entry.RankScore {
+			return hits[i].entry.ID < hits[j].entry.ID
+		}
+		return hits[i].entry.RankScore > hits[j].entry.RankScore
+	})
+
+	if uint64(len(hits)) > limit {
+		hits = hits[:limit]
+	}
+
+	entries := make([]*models.MemoryEntry, len(hits))
+	for i, hit := range hits {
+		entries[i] = hit.entry
+	}
+	return entries
+}
+
+// fuseRankings merges ranked result lists with weighted reciprocal rank fusion.
+// Each memory scores the sum of weight/(k + rank) over the lists it appears in,
+// normalized so the best fused hit has a relevance of 1.
+func fuseRankings(rankings []rankedList) []searchHit {
+	scores := make(map[string]float64)
+	entries := make(map[string]*models.MemoryEntry)
+
+	for _, ranking := range rankings {
+		for rank, entry := range ranking.entries {
+			scores[entry.ID] += ranking.weight / float64(rrfK+rank+1)
+
+			// Prefer the copy from the vector list so the raw similarity and matched chunk are preserved
+			if existing, exists := entries[entry.ID]; !exists || existing.Similarity == 0 {
+				entries[entry.ID] = entry
+			}
+		}
+	}
+
+	best := 0.0
+	for _, score := range scores {
+		if score > best {
+			best = score
+		}
+	}
+
+	hits := make([]searchHit, 0, len(entries))
+	for id, entry := range entries {
+		hits = append(hits, searchHit{entry: entry, relevance: scores[id] / best})
+	}
+
+	return hits
+}
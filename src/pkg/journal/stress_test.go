@@ -0,0 +1,274 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/llm"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/JaimeStill/persistent-context/pkg/vectordb"
+)
+
+// These tests drive the journal from many goroutines against the in-memory vector
+// database and the local LLM. They assert little beyond completion; their value is
+// running them with the race detector:
+//
+//	go test -race ./pkg/journal/
+
+const (
+	stressWorkers  = 8
+	stressCaptures = 25
+	stressDim      = 64
+)
+
+// newStressJournal builds a started journal over in-process fakes. The local LLM is
+// wrapped in a CachedLLM so concurrent captures also exercise the embedding cache.
+func newStressJournal(t *testing.T) (*VectorJournal, vectordb.VectorDB) {
+	t.Helper()
+	ctx := context.Background()
+
+	vectorDBConfig := &config.VectorDBConfig{
+		Provider:        "memory",
+		VectorDimension: stressDim,
+		MemoryCollections: map[string]string{
+			"episodic":      "episodic_memories",
+			"semantic":      "semantic_memories",
+			"procedural":    "procedural_memories",
+			"metacognitive": "metacognitive_memories",
+		},
+		AssociationsCollection: "associations",
+		VersionsCollection:     "memory_versions",
+		ChunksCollection:       "memory_chunks",
+	}
+	db, err := vectordb.NewVectorDB(vectorDBConfig)
+	if err != nil {
+		t.Fatalf("failed to create vector database: %v", err)
+	}
+	if err := db.Initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize vector database: %v", err)
+	}
+
+	llmConfig := &config.LLMConfig{
+		Provider:        "local",
+		Dimension:       stressDim,
+		CacheTTL:        time.Hour,
+		CacheMaxEntries: 64, // Small enough that eviction runs during the test
+	}
+	local, err := llm.NewLocalLLM(llmConfig)
+	if err != nil {
+		t.Fatalf("failed to create local LLM: %v", err)
+	}
+	cached, err := llm.NewCachedLLM(local, llmConfig)
+	if err != nil {
+		t.Fatalf("failed to create cached LLM: %v", err)
+	}
+
+	vj := NewVectorJournal(&Dependencies{
+		VectorDB:  db,
+		LLMClient: cached,
+		Config: &config.JournalConfig{
+			BatchSize:                10,
+			MaxMemorySize:            10000,
+			ConsolidatedDecay:        0.5,
+			ConsolidationBatchTokens: 4096,
+			ChunkTokens:              32,
+			ChunkOverlapTokens:       4,
+			AssociationWorkers:       4,
+			AssociationQueueSize:     16,
+		},
+		MemoryConfig: &config.MemoryConfig{
+			MaxTokens:    8000,
+			SafetyMargin: 0.8,
+		},
+		VectorDBConfig: vectorDBConfig,
+	})
+	if err := vj.Initialize(ctx); err != nil {
+		t.Fatalf("failed to initialize journal: %v", err)
+	}
+	if err := vj.Start(ctx); err != nil {
+		t.Fatalf("failed to start journal: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := vj.Stop(context.Background()); err != nil {
+			t.Errorf("failed to stop journal: %v", err)
+		}
+	})
+
+	return vj, db
+}
+
+// stressContent returns capture content that repeats across workers, so the embedding
+// cache sees both hits and misses. Every fifth capture is long enough to be chunked.
+func stressContent(worker, i int) string {
+	content := fmt.Sprintf("worker %d captured note %d about topic %d", worker%2, i, i%5)
+	if i%5 == 0 {
+		for line := 0; line < 20; line++ {
+			content += fmt.Sprintf("\nline %d of a long file capture on topic %d", line, i%5)
+		}
+	}
+	return content
+}
+
+// runParallel runs fn for every worker and capture index and fails on the first error
+func runParallel(t *testing.T, fn func(worker, i int) error) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, stressWorkers)
+	for worker := 0; worker < stressWorkers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < stressCaptures; i++ {
+				if err := fn(worker, i); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestParallelCaptures(t *testing.T) {
+	vj, db := newStressJournal(t)
+	ctx := context.Background()
+
+	runParallel(t, func(worker, i int) error {
+		if _, err := vj.CaptureContext(ctx, fmt.Sprintf("worker-%d", worker), stressContent(worker, i), nil); err != nil {
+			return fmt.Errorf("capture %d of worker %d: %w", i, worker, err)
+		}
+		return nil
+	})
+
+	count, err := db.Memories().Count(ctx, models.TypeEpisodic)
+	if err != nil {
+		t.Fatalf("failed to count memories: %v", err)
+	}
+	if want := uint64(stressWorkers * stressCaptures); count != want {
+		t.Errorf("stored %d memories, want %d", count, want)
+	}
+}
+
+func TestParallelCapturesWithReads(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	ctx := context.Background()
+
+	runParallel(t, func(worker, i int) error {
+		switch worker % 4 {
+		case 0:
+			captures := []*models.CaptureMemoryRequest{
+				{Source: "batch", Content: stressContent(worker, i)},
+				{Source: "batch", Content: stressContent(worker, i+1)},
+			}
+			if _, err := vj.CaptureBatch(ctx, captures); err != nil {
+				return fmt.Errorf("batch capture: %w", err)
+			}
+		case 1:
+			entry, err := vj.CaptureContext(ctx, "reader", stressContent(worker, i), nil)
+			if err != nil {
+				return fmt.Errorf("capture: %w", err)
+			}
+			if _, err := vj.GetMemoryByID(ctx, entry.ID); err != nil {
+				return fmt.Errorf("get memory: %w", err)
+			}
+			vj.GetMemoryAssociations(ctx, entry.ID)
+		case 2:
+			if _, err := vj.SearchMemories(ctx, fmt.Sprintf("topic %d", i%5), SearchOptions{Mode: models.SearchModeHybrid, Limit: 5}); err != nil {
+				return fmt.Errorf("search: %w", err)
+			}
+		default:
+			if _, err := vj.GetMemoryStats(ctx); err != nil {
+				return fmt.Errorf("stats: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func TestParallelAssociationTracker(t *testing.T) {
+	_, db := newStressJournal(t)
+	tracker := NewAssociationTracker(db)
+
+	runParallel(t, func(worker, i int) error {
+		source := fmt.Sprintf("memory-%d", i%5)
+		target := fmt.Sprintf("memory-%d", worker)
+
+		association := tracker.CreateAssociation(source, target, models.AssociationSemantic, 0.5, nil)
+
+		// Strengthen every association of the source while other workers read copies of them
+		for _, related := range tracker.GetAssociationsForMemory(source) {
+			tracker.UpdateAssociationStrength(related.ID, related.Strength+0.01)
+		}
+		if association.Strength != 0.5 {
+			return fmt.Errorf("created association changed to strength %v after it was returned", association.Strength)
+		}
+		tracker.GetRelatedMemoryIDs(target)
+
+		if i%10 == 9 {
+			tracker.RemoveAssociation(association.ID)
+		}
+		return nil
+	})
+}
+
+// slowEmbeddings delays embedding so concurrent edits overlap even on a single CPU
+type slowEmbeddings struct {
+	llm.LLM
+}
+
+func (s slowEmbeddings) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	time.Sleep(time.Millisecond)
+	return s.LLM.GenerateEmbeddings(ctx, texts)
+}
+
+func TestParallelUpdates(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	vj.llmClient = slowEmbeddings{vj.llmClient}
+	ctx := context.Background()
+
+	entry, err := vj.CaptureContext(ctx, "editor", "a memory edited by every worker", nil)
+	if err != nil {
+		t.Fatalf("failed to capture memory: %v", err)
+	}
+
+	// Readers write access statistics back while editors revise the same memory
+	runParallel(t, func(worker, i int) error {
+		if worker%2 == 0 {
+			_, err := vj.GetMemoryByID(ctx, entry.ID)
+			return err
+		}
+		content := stressContent(worker, i)
+		_, err := vj.UpdateMemory(ctx, entry.ID, models.MemoryUpdate{
+			Content: &content,
+			Editor:  fmt.Sprintf("worker-%d", worker),
+		})
+		return err
+	})
+
+	updated, err := vj.GetMemoryByID(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("failed to get memory: %v", err)
+	}
+	edits := stressWorkers / 2 * stressCaptures
+	if version := VersionNumber(updated); version != edits+1 {
+		t.Errorf("memory is at version %d after %d edits, want %d", version, edits, edits+1)
+	}
+
+	versions, err := vj.ListMemoryVersions(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("failed to list versions: %v", err)
+	}
+	if len(versions) != edits+1 {
+		t.Errorf("listed %d versions, want %d", len(versions), edits+1)
+	}
+}
@@ -2,8 +2,11 @@ package journal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,39 +22,125 @@ type VectorJournal struct {
 	llmClient      llm.LLM
 	config         *config.JournalConfig
 	vectorDBConfig *config.VectorDBConfig
+	memoryConfig   *config.MemoryConfig
 	scorer         *MemoryScorer
 	associations   *AssociationTracker
 	analyzer       *AssociationAnalyzer
-	counter        int64
+	workers        *analysisPool
+	retention      *retentionScheduler
+	retentionMu    sync.Mutex
+	locks          memoryLocks // Serializes updates to stored memories
+	counter        atomic.Int64
 }
 
 // NewVectorJournal creates a new vector-based journal implementation
 func NewVectorJournal(deps *Dependencies) *VectorJournal {
 	associations := NewAssociationTracker(deps.VectorDB)
 	
-	return &VectorJournal{
+	vj := &VectorJournal{
 		vectorDB:       deps.VectorDB,
 		llmClient:      deps.LLMClient,
 		config:         deps.Config,
 		vectorDBConfig: deps.VectorDBConfig,
+		memoryConfig:   deps.MemoryConfig,
 		scorer:         NewMemoryScorer(deps.MemoryConfig),
 		associations:   associations,
 		analyzer:       NewAssociationAnalyzer(associations),
-		counter:        time.Now().UnixNano(), // Use timestamp as base counter
 	}
+	vj.workers = newAnalysisPool(deps.Config.AssociationWorkers, deps.Config.AssociationQueueSize, vj.analyzeNewMemoryAssociations)
+	vj.retention = newRetentionScheduler(deps.Config.RetentionInterval, func(ctx context.Context) (*models.RetentionReport, error) {
+		return vj.ApplyRetention(ctx, false)
+	})
+	vj.counter.Store(time.Now().UnixNano()) // Use timestamp as base counter
+	
+	return vj
 }
 
-// CaptureContext implements the MCP interface for capturing context
+// Initialize hydrates the association tracker from the association collection
+func (vj *VectorJournal) Initialize(ctx context.Context) error {
+	loaded, err := vj.associations.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize journal: %w", err)
+	}
+
+	slog.Info("Loaded association graph", "associations", loaded)
+	return nil
+}
+
+// Start launches the association analysis workers and the retention schedule, which stop when ctx is cancelled
+func (vj *VectorJournal) Start(ctx context.Context) error {
+	vj.workers.Start(ctx)
+	vj.retention.Start(ctx)
+	return nil
+}
+
+// Stop halts scheduled retention and drains queued association analysis, cancelling what remains if ctx expires
+func (vj *VectorJournal) Stop(ctx context.Context) error {
+	if err := vj.retention.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop retention scheduler: %w", err)
+	}
+	if err := vj.workers.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop association workers: %w", err)
+	}
+	return nil
+}
+
+// CaptureContext implements the MCP interface for capturing context. Content longer
+// than the journal's chunk size is also stored as chunks so each section is searchable.
 func (vj *VectorJournal) CaptureContext(ctx context.Context, source string, content string, metadata map[string]any) (*models.MemoryEntry, error) {
-	vj.counter++
+	vj.counter.Add(1)
 	
 	// Generate embedding for the content
-	embedding, err := vj.llmClient.GenerateEmbedding(ctx, content)
+	captured, err := vj.embedCaptures(ctx, []string{content})
 	if err != nil {
 		slog.Error("Failed to generate embedding", "error", err, "source", source)
 		return nil, fmt.Errorf("failed to generate embedding: %w", err)
 	}
 
+	entry := vj.newEpisodicEntry(source, content, metadata, captured[0].embedding)
+	if err := vj.storeCaptured(ctx, entry, captured[0]); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// CaptureBatch captures several memories, embedding their contents together so bulk
+// imports need a few batch requests instead of one request per memory. Memories are
+// stored in order; if one fails, those before it remain captured.
+func (vj *VectorJournal) CaptureBatch(ctx context.Context, captures []*models.CaptureMemoryRequest) ([]*models.MemoryEntry, error) {
+	if len(captures) == 0 {
+		return []*models.MemoryEntry{}, nil
+	}
+
+	contents := make([]string, len(captures))
+	for i, capture := range captures {
+		contents[i] = capture.Content
+	}
+
+	captured, err := vj.embedCaptures(ctx, contents)
+	if err != nil {
+		slog.Error("Failed to generate batch embeddings", "error", err, "count", len(captures))
+		return nil, fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+
+	entries := make([]*models.MemoryEntry, 0, len(captures))
+	for i, capture := range captures {
+		vj.counter.Add(1)
+
+		entry := vj.newEpisodicEntry(capture.Source, capture.Content, capture.Metadata, captured[i].embedding)
+		if err := vj.storeCaptured(ctx, entry, captured[i]); err != nil {
+			return entries, fmt.Errorf("memory %d of %d: %w", i+1, len(captures), err)
+		}
+		entries = append(entries, entry)
+	}
+
+	slog.Info("Captured memory batch", "count", len(entries))
+	return entries, nil
+}
+
+// newEpisodicEntry builds a scored episodic memory for captured content
+func (vj *VectorJournal) newEpisodicEntry(source string, content string, metadata map[string]any, embedding []float32) *models.MemoryEntry {
 	// Create memory entry
 	entry := &models.MemoryEntry{
 		ID:            uuid.New().String(),
@@ -75,32 +164,53 @@ func (vj *VectorJournal) CaptureContext(ctx context.Context, source string, cont
 	// Initialize memory scoring
 	entry.Score = vj.scorer.ScoreMemory(entry)
 	
+	return entry
+}
+
+// storeCaptured stores a captured memory and its chunks, then queues its association analysis.
+// Chunks are stored first so a memory is never visible without them.
+func (vj *VectorJournal) storeCaptured(ctx context.Context, entry *models.MemoryEntry, captured capturedEmbedding) error {
+	if err := vj.storeChunks(ctx, entry, captured.chunks, captured.chunkEmbeddings); err != nil {
+		slog.Error("Failed to store memory chunks", "error", err, "id", entry.ID)
+		return err
+	}
+
 	// Store in vector database
 	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
 		slog.Error("Failed to store memory in vector database", "error", err, "id", entry.ID)
-		return nil, fmt.Errorf("failed to store memory: %w", err)
+		return fmt.Errorf("failed to store memory: %w", err)
 	}
 	
 	slog.Info("Memory captured and stored",
-		"source", source,
+		"source", entry.Metadata["source"],
 		"id", entry.ID,
-		"content_length", len(content),
-		"embedding_dim", len(embedding))
+		"content_length", len(entry.Content),
+		"chunks", len(captured.chunks),
+		"embedding_dim", len(entry.Embedding))
 	
-	// Analyze associations with recent memories (use background context for async operation)
-	go vj.analyzeNewMemoryAssociations(context.Background(), entry)
+	// Queue association analysis with recent memories. Submit blocks while the queue is
+	// full; the analysis gets its own copy because it updates AssociationIDs later.
+	analyzed := *entry
+	if err := vj.workers.Submit(ctx, &analyzed); err != nil {
+		slog.Warn("Skipped association analysis", "error", err, "id", entry.ID)
+	}
 	
-	return entry, nil
+	return nil
 }
 
 // GetMemories retrieves recent memories from episodic storage
 func (vj *VectorJournal) GetMemories(ctx context.Context, limit uint32) ([]*models.MemoryEntry, error) {
+	return vj.ListMemories(ctx, limit, nil)
+}
+
+// ListMemories retrieves recent episodic memories matching an optional filter
+func (vj *VectorJournal) ListMemories(ctx context.Context, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
 	if limit == 0 {
 		limit = vj.config.BatchSize
 	}
 
 	// Get recent memories without similarity search
-	memories, err := vj.vectorDB.Memories().GetRecent(ctx, models.TypeEpisodic, limit)
+	memories, err := vj.vectorDB.Memories().GetRecent(ctx, models.TypeEpisodic, limit, filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get recent memories: %w", err)
 	}
@@ -108,11 +218,15 @@ func (vj *VectorJournal) GetMemories(ctx context.Context, limit uint32) ([]*mode
 	return memories, nil
 }
 
-// GetMemoryByID retrieves a specific memory by ID
+// GetMemoryByID retrieves a specific memory by ID from whichever collection holds it.
+// The returned error wraps vectordb.ErrMemoryNotFound when no collection does.
 func (vj *VectorJournal) GetMemoryByID(ctx context.Context, id string) (*models.MemoryEntry, error) {
-	entry, err := vj.vectorDB.Memories().Retrieve(ctx, models.TypeEpisodic, id)
+	// Hold the memory's lock so the access write-back cannot revert a concurrent edit
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve memory %s: %w", id, err)
+		return nil, err
 	}
 
 	// Update access tracking using enhanced scoring system
@@ -126,6 +240,31 @@ func (vj *VectorJournal) GetMemoryByID(ctx context.Context, id string) (*models.
 	return entry, nil
 }
 
+// GetMemoryAssociations returns the associations in which a memory is the source or target
+func (vj *VectorJournal) GetMemoryAssociations(ctx context.Context, id string) ([]*models.MemoryAssociation, error) {
+	return vj.associations.GetAssociationsForMemory(id), nil
+}
+
+// GetSourceMemories returns the memories a consolidated memory was created from.
+// Sources that have since been deleted are skipped.
+func (vj *VectorJournal) GetSourceMemories(ctx context.Context, entry *models.MemoryEntry) ([]*models.MemoryEntry, error) {
+	sourceIDs := vj.sourceIDs(entry)
+	sources := make([]*models.MemoryEntry, 0, len(sourceIDs))
+
+	for _, sourceID := range sourceIDs {
+		source, err := vj.locateMemory(ctx, sourceID)
+		if errors.Is(err, vectordb.ErrMemoryNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve source memories: %w", err)
+		}
+		sources = append(sources, source)
+	}
+
+	return sources, nil
+}
+
 // QuerySimilarMemories finds memories similar to the given content
 func (vj *VectorJournal) QuerySimilarMemories(ctx context.Context, content string, memType models.MemoryType, limit uint64) ([]*models.MemoryEntry, error) {
 	// Generate embedding for the query content
@@ -139,7 +278,7 @@ func (vj *VectorJournal) QuerySimilarMemories(ctx context.Context, content strin
 	}
 
 	// Query vector database
-	memories, err := vj.vectorDB.Memories().Query(ctx, memType, embedding, limit)
+	memories, err := vj.vectorDB.Memories().Query(ctx, memType, embedding, limit, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query similar memories: %w", err)
 	}
@@ -148,57 +287,93 @@ func (vj *VectorJournal) QuerySimilarMemories(ctx context.Context, content strin
 }
 
 
-// ConsolidateMemories processes episodic memories into semantic knowledge
-func (vj *VectorJournal) ConsolidateMemories(ctx context.Context, memories []*models.MemoryEntry) error {
+// ConsolidateMemories processes episodic memories into semantic knowledge and returns
+// the stored semantic summary, or nil when there is nothing to consolidate. The
+// concepts, patterns and insights extracted alongside the summary are stored in the
+// semantic, procedural and metacognitive collections and listed in its knowledge_records.
+// Groups larger than one prompt's token budget are consolidated hierarchically. Sources
+// are marked consolidated unless the summary is an extractive fallback (see IsExtractive),
+// and any earlier extractive summary of them is deleted once the new memory is stored.
+func (vj *VectorJournal) ConsolidateMemories(ctx context.Context, memories []*models.MemoryEntry, opts ConsolidateOptions) (*models.MemoryEntry, error) {
 	if len(memories) == 0 {
-		return nil
+		return nil, nil
 	}
 
-	// Extract content for consolidation
-	memoryTexts := make([]string, len(memories))
-	for i, mem := range memories {
-		memoryTexts[i] = mem.Content
+	// Callers may have listed the sources before another consolidation took them
+	memories, err := vj.unconsolidatedSources(ctx, memories)
+	if err != nil {
+		return nil, err
 	}
 
-	// Use LLM to consolidate memories
-	consolidatedContent, err := vj.llmClient.ConsolidateMemories(ctx, memoryTexts)
+	// Consolidate in token-bounded batches so large groups don't overrun the model
+	knowledge, plan, err := vj.consolidateHierarchically(ctx, memories, opts)
 	if err != nil {
-		return fmt.Errorf("failed to consolidate memories: %w", err)
+		return nil, err
 	}
 
 	// Generate embedding for consolidated content
-	embedding, err := vj.llmClient.GenerateEmbedding(ctx, consolidatedContent)
+	embedding, err := vj.llmClient.GenerateEmbedding(ctx, knowledge.Summary)
 	if err != nil {
-		return fmt.Errorf("failed to generate embedding for consolidated memory: %w", err)
+		return nil, fmt.Errorf("failed to generate embedding for consolidated memory: %w", err)
 	}
 
 	// Create semantic memory entry
+	now := time.Now()
 	semanticEntry := &models.MemoryEntry{
 		ID:        uuid.New().String(),
 		Type:      models.TypeSemantic,
-		Content:   consolidatedContent,
+		Content:   knowledge.Summary,
 		Embedding: embedding,
 		Metadata: map[string]any{
 			"source_memories": len(memories),
-			"consolidation_timestamp": time.Now().Unix(),
+			"consolidation_timestamp": now.Unix(),
 			"consolidated_from": extractMemoryIDs(memories),
+			"consolidation_batches": plan.batches,
 		},
-		CreatedAt:  time.Now(),
-		AccessedAt: time.Now(),
+		CreatedAt:  now,
+		AccessedAt: now,
 		Strength:   1.0,
 	}
 
+	if plan.levels > 0 {
+		semanticEntry.Metadata["consolidation_levels"] = plan.levels
+	}
+	if plan.extractive {
+		semanticEntry.Metadata["extractive"] = true
+	}
+
+	// Store the typed records first so the summary can list them
+	if records := vj.storeKnowledge(ctx, knowledge, semanticEntry.ID, now); len(records) > 0 {
+		semanticEntry.Metadata["knowledge_records"] = records
+	}
+
 	// Store semantic memory
 	if err := vj.vectorDB.Memories().Store(ctx, semanticEntry); err != nil {
-		return fmt.Errorf("failed to store semantic memory: %w", err)
+		return nil, fmt.Errorf("failed to store semantic memory: %w", err)
+	}
+
+	// Record provenance and mark the sources so later runs don't consolidate them again.
+	// Extractive summaries leave their sources for a real consolidation once the model is
+	// back; either way this memory replaces any earlier extractive summary of them.
+	vj.linkSources(semanticEntry.ID, extractMemoryIDs(memories), now)
+	if plan.extractive {
+		vj.markExtractive(ctx, memories, semanticEntry.ID)
+	} else {
+		vj.markConsolidated(ctx, memories, semanticEntry.ID, now)
 	}
+	vj.retireExtractive(ctx, extractiveSummaryIDs(memories))
 
 	slog.Info("Consolidated memories into semantic knowledge",
 		"semantic_id", semanticEntry.ID,
+		"extractive", plan.extractive,
 		"source_count", len(memories),
-		"content_length", len(consolidatedContent))
+		"batches", plan.batches,
+		"content_length", len(knowledge.Summary),
+		"concepts", len(knowledge.Concepts),
+		"patterns", len(knowledge.Patterns),
+		"insights", len(knowledge.Insights))
 
-	return nil
+	return semanticEntry, nil
 }
 
 // GetMemoryStats returns statistics about stored memories
@@ -232,6 +407,17 @@ func (vj *VectorJournal) GetMemoryStats(ctx context.Context) (map[string]any, er
 		"procedural_memories":    proceduralCount,
 		"metacognitive_memories": metacognitiveCount,
 		"total_memories":         totalCount,
+		"association_workers":    vj.workers.Stats(),
+		"retention":              vj.retention.Stats(),
+	}
+
+	// Chunks are sections of episodic memories, so they are not part of the total
+	if vj.vectorDBConfig.ChunksCollection != "" {
+		chunks, err := vj.vectorDB.Memories().Count(ctx, models.TypeChunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count memory chunks: %w", err)
+		}
+		stats["memory_chunks"] = chunks
 	}
 
 	return stats, nil
@@ -261,6 +447,24 @@ func extractMemoryIDs(memories []*models.MemoryEntry) []string {
 	return ids
 }
 
+// metadataStrings reads a list-valued metadata field, which decodes as []any from persistent stores
+func metadataStrings(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
 // analyzeNewMemoryAssociations runs association analysis for a newly created memory
 func (vj *VectorJournal) analyzeNewMemoryAssociations(ctx context.Context, newMemory *models.MemoryEntry) {
 	// Get recent memories for association analysis
@@ -279,14 +483,9 @@ func (vj *VectorJournal) analyzeNewMemoryAssociations(ctx context.Context, newMe
 	// Analyze contextual associations (same source)
 	vj.analyzer.AnalyzeContextualAssociations(ctx, newMemory, recentMemories)
 	
-	// Update memory with association IDs
 	associationIDs := vj.associations.GetRelatedMemoryIDs(newMemory.ID)
-	if len(associationIDs) > 0 {
-		newMemory.AssociationIDs = associationIDs
-		// Store updated memory (fire and forget, don't block on errors)
-		if err := vj.vectorDB.Memories().Store(ctx, newMemory); err != nil {
-			slog.Warn("Failed to update memory with associations", "error", err, "id", newMemory.ID)
-		}
+	if err := vj.storeAssociationIDs(ctx, newMemory, associationIDs); err != nil {
+		slog.Warn("Failed to update memory with associations", "error", err, "id", newMemory.ID)
 	}
 	
 	slog.Info("Association analysis complete",
@@ -294,3 +493,28 @@ func (vj *VectorJournal) analyzeNewMemoryAssociations(ctx context.Context, newMe
 		"associations_found", len(associationIDs))
 }
 
+
+// storeAssociationIDs records association IDs on the stored copy of a newly analyzed
+// memory, which may have been edited while it was queued. If it was deleted instead,
+// the associations the analysis just created are dropped.
+func (vj *VectorJournal) storeAssociationIDs(ctx context.Context, newMemory *models.MemoryEntry, associationIDs []string) error {
+	unlock := vj.locks.lock(newMemory.ID)
+	current, err := vj.vectorDB.Memories().Retrieve(ctx, newMemory.Type, newMemory.ID)
+	if errors.Is(err, vectordb.ErrMemoryNotFound) {
+		unlock()
+		if _, err := vj.associations.RemoveMemory(ctx, newMemory.ID); err != nil {
+			return fmt.Errorf("failed to remove associations of deleted memory: %w", err)
+		}
+		return nil
+	}
+	defer unlock()
+	if err != nil {
+		return err
+	}
+
+	if len(associationIDs) == 0 {
+		return nil
+	}
+	current.AssociationIDs = associationIDs
+	return vj.vectorDB.Memories().Store(ctx, current)
+}
@@ -0,0 +1,245 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// ErrVersionNotFound is returned when a memory has no stored revision with the requested number
+var ErrVersionNotFound = errors.New("memory version not found")
+
+// ErrInvalidUpdate is returned when an edit or restore request cannot be applied as given
+var ErrInvalidUpdate = errors.New("invalid memory update")
+
+// managedMetadataKeys are maintained by the journal rather than by editors. They are
+// left out of version snapshots and carried over unchanged when a memory is revised,
+// so edits and restores cannot break consolidation lineage or rollback.
+var managedMetadataKeys = []string{
+	"version", "edited_at", "edited_by", "access_count", "last_access", "chunk_count", "parent_id",
+	"consolidated_into", "consolidated_at", "strength_before_consolidation", "extractive_into",
+	"consolidated_from", "source_memories", "consolidation_batches", "consolidation_levels",
+	"consolidation_timestamp", "consolidation_id", "knowledge_records", "knowledge_kind", "extractive",
+}
+
+// UpdateMemory edits a memory's content and/or metadata. The replaced revision is kept
+// in the version collection and changed content is re-embedded.
+func (vj *VectorJournal) UpdateMemory(ctx context.Context, id string, update models.MemoryUpdate) (*models.MemoryEntry, error) {
+	if err := validateMemoryUpdate(update); err != nil {
+		return nil, err
+	}
+
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content := entry.Content
+	if update.Content != nil {
+		content = *update.Content
+	}
+
+	metadata := editableMetadata(entry.Metadata)
+	for key, value := range update.Metadata {
+		if value == nil {
+			delete(metadata, key)
+		} else {
+			metadata[key] = value
+		}
+	}
+
+	return vj.reviseMemory(ctx, entry, content, metadata, update.Editor)
+}
+
+// ListMemoryVersions returns every revision of a memory ordered by version number,
+// ending with the current one
+func (vj *VectorJournal) ListMemoryVersions(ctx context.Context, id string) ([]*models.MemoryVersion, error) {
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := vj.vectorDB.Versions().GetByMemoryID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of memory %s: %w", id, err)
+	}
+
+	return append(versions, currentVersion(entry)), nil
+}
+
+// RestoreMemoryVersion makes an earlier revision current again. The restore is itself
+// recorded as a new revision, so no history is lost.
+func (vj *VectorJournal) RestoreMemoryVersion(ctx context.Context, id string, version int, editor string) (*models.MemoryEntry, error) {
+	defer vj.locks.lock(id)()
+
+	entry, err := vj.locateMemory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == VersionNumber(entry) {
+		return nil, fmt.Errorf("%w: version %d is already the current version of memory %s", ErrInvalidUpdate, version, id)
+	}
+
+	versions, err := vj.vectorDB.Versions().GetByMemoryID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of memory %s: %w", id, err)
+	}
+
+	for _, candidate := range versions {
+		if candidate.Version == version {
+			return vj.reviseMemory(ctx, entry, candidate.Content, candidate.Metadata, editor)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: version %d of memory %s", ErrVersionNotFound, version, id)
+}
+
+// reviseMemory snapshots the current revision, then stores the memory with new content
+// and metadata. Callers hold the memory's lock.
+func (vj *VectorJournal) reviseMemory(ctx context.Context, entry *models.MemoryEntry, content string, metadata map[string]any, editor string) (*models.MemoryEntry, error) {
+	if editor == "" {
+		editor = "unknown"
+	}
+
+	// Embed before writing anything so a failed embedding leaves no partial revision.
+	// Episodic content is re-chunked the same way it is when captured.
+	var captured *capturedEmbedding
+	embedding := entry.Embedding
+	if content != entry.Content && entry.Type == models.TypeEpisodic {
+		embedded, err := vj.embedCaptures(ctx, []string{content})
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+		captured = &embedded[0]
+		embedding = captured.embedding
+	} else if content != entry.Content {
+		var err error
+		if embedding, err = vj.llmClient.GenerateEmbedding(ctx, content); err != nil {
+			return nil, fmt.Errorf("failed to generate embedding: %w", err)
+		}
+	}
+
+	previous := currentVersion(entry)
+	if err := vj.vectorDB.Versions().Store(ctx, previous); err != nil {
+		return nil, fmt.Errorf("failed to store previous version of memory %s: %w", entry.ID, err)
+	}
+
+	// Restored snapshots taken before a key was managed may still carry it
+	metadata = editableMetadata(metadata)
+	for _, key := range managedMetadataKeys {
+		if value, exists := entry.Metadata[key]; exists {
+			metadata[key] = value
+		}
+	}
+	metadata["version"] = previous.Version + 1
+	metadata["edited_at"] = time.Now().Unix()
+	metadata["edited_by"] = editor
+
+	entry.Content = content
+	entry.Embedding = embedding
+	entry.Metadata = metadata
+
+	// Changed content replaces the memory's chunks
+	if captured != nil {
+		if err := vj.storeChunks(ctx, entry, captured.chunks, captured.chunkEmbeddings); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := vj.vectorDB.Memories().Store(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to store updated memory %s: %w", entry.ID, err)
+	}
+
+	slog.Info("Memory revised",
+		"id", entry.ID,
+		"version", previous.Version+1,
+		"editor", editor,
+		"content_changed", content != previous.Content)
+
+	return entry, nil
+}
+
+// validateMemoryUpdate rejects empty edits and edits to journal-managed metadata
+func validateMemoryUpdate(update models.MemoryUpdate) error {
+	if update.Content == nil && len(update.Metadata) == 0 {
+		return fmt.Errorf("%w: update must change content or metadata", ErrInvalidUpdate)
+	}
+
+	if update.Content != nil && *update.Content == "" {
+		return fmt.Errorf("%w: content cannot be empty", ErrInvalidUpdate)
+	}
+
+	for _, key := range managedMetadataKeys {
+		if _, exists := update.Metadata[key]; exists {
+			return fmt.Errorf("%w: metadata key %q is managed by the journal", ErrInvalidUpdate, key)
+		}
+	}
+
+	return nil
+}
+
+// currentVersion snapshots a memory's current content and metadata as a version
+func currentVersion(entry *models.MemoryEntry) *models.MemoryVersion {
+	version := &models.MemoryVersion{
+		MemoryID: entry.ID,
+		Version:  VersionNumber(entry),
+		Content:  entry.Content,
+		Metadata: editableMetadata(entry.Metadata),
+		EditedAt: entry.CreatedAt,
+	}
+
+	// Unedited memories were written by their capture source
+	if source, ok := entry.Metadata["source"].(string); ok {
+		version.EditedBy = source
+	}
+	if editor, ok := entry.Metadata["edited_by"].(string); ok {
+		version.EditedBy = editor
+	}
+	if editedAt, ok := metadataInt(entry.Metadata["edited_at"]); ok {
+		version.EditedAt = time.Unix(editedAt, 0)
+	}
+
+	return version
+}
+
+// VersionNumber returns a memory's current revision number; unedited memories are version 1
+func VersionNumber(entry *models.MemoryEntry) int {
+	if version, ok := metadataInt(entry.Metadata["version"]); ok && version > 0 {
+		return int(version)
+	}
+	return 1
+}
+
+// editableMetadata copies metadata without the journal-managed keys
+func editableMetadata(metadata map[string]any) map[string]any {
+	editable := maps.Clone(metadata)
+	if editable == nil {
+		editable = make(map[string]any)
+	}
+	for _, key := range managedMetadataKeys {
+		delete(editable, key)
+	}
+	return editable
+}
+
+// metadataInt reads an integer metadata value, which decodes as int64 or float64 from persistent stores
+func metadataInt(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,85 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// lineageKeys are the managed keys consolidation lineage and rollback depend on
+var lineageKeys = []string{
+	"consolidated_from", "consolidated_into", "consolidated_at", "knowledge_records",
+	"extractive", "extractive_into", "consolidation_id", "consolidation_timestamp", "parent_id",
+}
+
+// consolidateSources consolidates a few captured episodes with the local model
+func consolidateSources(t *testing.T, vj *VectorJournal) (*models.MemoryEntry, []*models.MemoryEntry) {
+	t.Helper()
+
+	sources := captureSources(t, vj)
+	semantic, err := vj.ConsolidateMemories(context.Background(), sources, ConsolidateOptions{})
+	if err != nil {
+		t.Fatalf("failed to consolidate: %v", err)
+	}
+	return semantic, sources
+}
+
+func TestUpdateRejectsLineageMetadata(t *testing.T) {
+	vj, _ := newStressJournal(t)
+	semantic, _ := consolidateSources(t, vj)
+
+	for _, key := range lineageKeys {
+		for _, value := range []any{"tampered", nil} {
+			_, err := vj.UpdateMemory(context.Background(), semantic.ID, models.MemoryUpdate{
+				Metadata: map[string]any{key: value},
+				Editor:   "test",
+			})
+			if !errors.Is(err, ErrInvalidUpdate) {
+				t.Errorf("setting %s to %v returned %v, want %v", key, value, err, ErrInvalidUpdate)
+			}
+		}
+	}
+}
+
+func TestRestoreKeepsLineageMetadata(t *testing.T) {
+	vj, db := newStressJournal(t)
+	ctx := context.Background()
+	semantic, sources := consolidateSources(t, vj)
+
+	content := "a corrected summary"
+	if _, err := vj.UpdateMemory(ctx, semantic.ID, models.MemoryUpdate{Content: &content, Editor: "test"}); err != nil {
+		t.Fatalf("failed to update memory: %v", err)
+	}
+
+	// Forge lineage into the stored snapshot, as one written before the keys were managed could hold
+	versions, err := db.Versions().GetByMemoryID(ctx, semantic.ID)
+	if err != nil || len(versions) != 1 {
+		t.Fatalf("listed %d versions (%v), want 1", len(versions), err)
+	}
+	versions[0].Metadata["consolidated_from"] = []string{"forged"}
+	versions[0].Metadata["extractive"] = true
+	if err := db.Versions().Store(ctx, versions[0]); err != nil {
+		t.Fatalf("failed to store version: %v", err)
+	}
+
+	restored, err := vj.RestoreMemoryVersion(ctx, semantic.ID, versions[0].Version, "test")
+	if err != nil {
+		t.Fatalf("failed to restore version: %v", err)
+	}
+	if IsExtractive(restored) {
+		t.Error("restore marked the memory extractive")
+	}
+
+	from := metadataStrings(restored.Metadata["consolidated_from"])
+	for _, source := range sources {
+		if !slices.Contains(from, source.ID) {
+			t.Errorf("restored consolidated_from %v lost source %s", from, source.ID)
+		}
+	}
+	if slices.Contains(from, "forged") {
+		t.Errorf("restored consolidated_from %v took the forged source", from)
+	}
+}
@@ -0,0 +1,165 @@
+package journal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// analysisPool runs association analysis on a fixed number of workers fed by a
+// bounded queue. Submit blocks while the queue is full, applying backpressure
+// to callers instead of spawning unbounded goroutines.
+type analysisPool struct {
+	workers   int
+	queueSize int
+	queue     chan *models.MemoryEntry
+	handle    func(ctx context.Context, entry *models.MemoryEntry)
+	mu        sync.RWMutex
+	running   bool
+	done      chan struct{}   // Closed by Stop to release blocked submitters
+	workerCtx context.Context // Ends with the host context or when Stop cancels the workers
+	cancel    context.CancelFunc
+	senders   sync.WaitGroup // Submits that may still send on the queue
+	wg        sync.WaitGroup
+	active    atomic.Int64
+	processed atomic.Uint64
+}
+
+// newAnalysisPool creates a worker pool with the given concurrency and queue capacity
+func newAnalysisPool(workers, queueSize int, handle func(ctx context.Context, entry *models.MemoryEntry)) *analysisPool {
+	return &analysisPool{
+		workers:   workers,
+		queueSize: queueSize,
+		queue:     make(chan *models.MemoryEntry, queueSize),
+		handle:    handle,
+	}
+}
+
+// Start launches the workers; they stop when ctx is cancelled or Stop is called
+func (p *analysisPool) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return
+	}
+
+	// A stopped pool has closed its queue, so each start gets a fresh one
+	workerCtx, cancel := context.WithCancel(ctx)
+	p.queue = make(chan *models.MemoryEntry, p.queueSize)
+	p.done = make(chan struct{})
+	p.workerCtx = workerCtx
+	p.cancel = cancel
+	p.running = true
+
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.work(workerCtx, p.queue)
+	}
+
+	slog.Info("Association worker pool started", "workers", p.workers, "queue_size", cap(p.queue))
+}
+
+// Submit queues an entry for analysis, blocking while the queue is full until ctx is
+// done, the workers have stopped or Stop is called. The lock is only held to register
+// the send, so a blocked Submit never holds up Stop.
+func (p *analysisPool) Submit(ctx context.Context, entry *models.MemoryEntry) error {
+	p.mu.RLock()
+	if !p.running {
+		p.mu.RUnlock()
+		return fmt.Errorf("association worker pool is not running")
+	}
+	queue, done, workerCtx := p.queue, p.done, p.workerCtx
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	select {
+	case queue <- entry:
+		return nil
+	case <-done:
+		return fmt.Errorf("association worker pool is stopping")
+	case <-workerCtx.Done():
+		return fmt.Errorf("association worker pool has stopped: %w", workerCtx.Err())
+	case <-ctx.Done():
+		return fmt.Errorf("failed to queue association analysis: %w", ctx.Err())
+	}
+}
+
+// Stop stops accepting work and drains the queue. If ctx expires first, pending
+// and in-flight analysis is cancelled. Workers have exited when Stop returns.
+func (p *analysisPool) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil
+	}
+	p.running = false
+	close(p.done)
+	p.mu.Unlock()
+
+	// Blocked submitters return once done is closed; the queue is closed only after
+	// the last of them has given up sending on it
+	p.senders.Wait()
+	close(p.queue)
+
+	drained := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		p.cancel()
+		slog.Info("Association worker pool drained")
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-drained
+		return fmt.Errorf("association worker pool cancelled with %d pending: %w", len(p.queue), ctx.Err())
+	}
+}
+
+// work processes queued entries until the queue is closed and drained or ctx is cancelled
+func (p *analysisPool) work(ctx context.Context, queue <-chan *models.MemoryEntry) {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case entry, ok := <-queue:
+			if !ok {
+				return // Queue closed and drained
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			p.active.Add(1)
+			p.handle(ctx, entry)
+			p.active.Add(-1)
+			p.processed.Add(1)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stats reports queue depth and worker activity
+func (p *analysisPool) Stats() map[string]any {
+	p.mu.RLock()
+	queue := p.queue
+	p.mu.RUnlock()
+
+	return map[string]any{
+		"workers":        p.workers,
+		"queue_depth":    len(queue),
+		"queue_capacity": cap(queue),
+		"active":         p.active.Load(),
+		"processed":      p.processed.Load(),
+	}
+}
@@ -0,0 +1,96 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// blockingPool returns a started single-worker pool whose worker blocks until release is
+// closed, with its one-entry queue already full
+func blockingPool(t *testing.T, ctx context.Context, release chan struct{}) *analysisPool {
+	t.Helper()
+
+	started := make(chan struct{}, 1)
+	pool := newAnalysisPool(1, 1, func(ctx context.Context, entry *models.MemoryEntry) {
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+	})
+	pool.Start(ctx)
+
+	if err := pool.Submit(context.Background(), &models.MemoryEntry{}); err != nil {
+		t.Fatalf("failed to submit first entry: %v", err)
+	}
+	<-started
+	if err := pool.Submit(context.Background(), &models.MemoryEntry{}); err != nil {
+		t.Fatalf("failed to fill queue: %v", err)
+	}
+	return pool
+}
+
+// submitAsync submits an entry in the background and returns its result channel
+func submitAsync(pool *analysisPool) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- pool.Submit(context.Background(), &models.MemoryEntry{})
+	}()
+	return result
+}
+
+func TestStopReleasesBlockedSubmit(t *testing.T) {
+	release := make(chan struct{})
+	pool := blockingPool(t, context.Background(), release)
+
+	blocked := submitAsync(pool)
+	time.Sleep(20 * time.Millisecond) // Let the submit block on the full queue
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- pool.Stop(stopCtx) }()
+
+	select {
+	case err := <-blocked:
+		if err == nil {
+			t.Error("blocked submit succeeded after Stop")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not release the blocked submit")
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop did not return")
+	}
+	close(release)
+}
+
+func TestHostCancelReleasesBlockedSubmit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	pool := blockingPool(t, ctx, release)
+
+	blocked := submitAsync(pool)
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-blocked:
+		if err == nil {
+			t.Error("blocked submit succeeded after the host context ended")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("host cancellation did not release the blocked submit")
+	}
+
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Errorf("failed to stop pool: %v", err)
+	}
+}
@@ -0,0 +1,388 @@
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	_ "modernc.org/sqlite"
+)
+
+// cachePruneInterval is how many disk writes pass between trims of the persistent cache
+const cachePruneInterval = 1000
+
+// CachedLLM wraps an LLM with a bounded embedding cache. Entries are keyed by a hash of
+// the provider, embedding model, dimension and text, expire after the configured TTL and
+// are evicted least recently used first. With a cache path the entries are also written
+// to SQLite so they survive restarts. Callers get their own copy of every embedding, so
+// changing one never alters the cache. Consolidation calls pass through uncached.
+type CachedLLM struct {
+	LLM
+	namespace  string        // Provider, model and dimension, so changing any never returns stale vectors
+	ttl        time.Duration // How long an embedding stays valid after it is generated
+	maxEntries int           // Entries kept in memory and on disk
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List  // Most recently used first
+	store      *cacheStore // Persistent copy, nil when the cache is memory only
+	stats      models.CacheStats
+}
+
+// cacheEntry is a cached embedding and the time it expires
+type cacheEntry struct {
+	key       string
+	embedding []float32
+	expires   time.Time
+}
+
+// NewCachedLLM wraps an LLM with an embedding cache configured by the cache settings
+func NewCachedLLM(inner LLM, config *config.LLMConfig) (*CachedLLM, error) {
+	c := &CachedLLM{
+		LLM:        inner,
+		namespace:  fmt.Sprintf("%s\x00%s\x00%d", config.Provider, config.EmbeddingModel, config.Dimension),
+		ttl:        config.CacheTTL,
+		maxEntries: config.CacheMaxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+	c.stats.Enabled = true
+	c.stats.MaxEntries = config.CacheMaxEntries
+
+	if config.CachePath != "" {
+		store, err := openCacheStore(config.CachePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.prune(context.Background(), time.Now(), c.maxEntries); err != nil {
+			store.close()
+			return nil, err
+		}
+
+		c.store = store
+		c.stats.Persistent = true
+	}
+
+	return c, nil
+}
+
+// GenerateEmbedding returns the cached embedding for text or generates and caches it
+func (c *CachedLLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	key := c.key(text)
+	if embedding, ok := c.lookup(ctx, key); ok {
+		return embedding, nil
+	}
+
+	embedding, err := c.LLM.GenerateEmbedding(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(ctx, key, embedding)
+	return embedding, nil
+}
+
+// GenerateEmbeddings serves cached texts from the cache and generates the rest in one
+// batch. Repeated texts within the batch are generated once.
+func (c *CachedLLM) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	pending := make(map[string][]int)
+	var keys []string
+	var missing []string
+
+	for i, text := range texts {
+		key := c.key(text)
+		if indexes, queued := pending[key]; queued {
+			pending[key] = append(indexes, i)
+			continue
+		}
+
+		if embedding, ok := c.lookup(ctx, key); ok {
+			embeddings[i] = embedding
+			continue
+		}
+
+		pending[key] = []int{i}
+		keys = append(keys, key)
+		missing = append(missing, text)
+	}
+
+	if len(missing) == 0 {
+		return embeddings, nil
+	}
+
+	generated, err := c.LLM.GenerateEmbeddings(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, key := range keys {
+		for n, index := range pending[key] {
+			if n == 0 {
+				embeddings[index] = generated[i]
+			} else {
+				embeddings[index] = slices.Clone(generated[i])
+			}
+		}
+		c.add(ctx, key, generated[i])
+	}
+
+	return embeddings, nil
+}
+
+// ClearCache removes every cached embedding, including the persistent copy
+func (c *CachedLLM) ClearCache() {
+	c.mu.Lock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.mu.Unlock()
+
+	if c.store != nil {
+		if err := c.store.clear(context.Background()); err != nil {
+			slog.Warn("Failed to clear persistent embedding cache", "error", err)
+		}
+	}
+
+	c.LLM.ClearCache()
+}
+
+// Stats returns the cache's hit, miss and eviction counters
+func (c *CachedLLM) Stats() models.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// Close releases the persistent store
+func (c *CachedLLM) Close() error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.close()
+}
+
+// key hashes text together with the provider, model and dimension it is embedded by
+func (c *CachedLLM) key(text string) string {
+	sum := sha256.Sum256([]byte(c.namespace + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookup returns a copy of an unexpired embedding from memory or, failing that, from disk
+func (c *CachedLLM) lookup(ctx context.Context, key string) ([]float32, bool) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*cacheEntry)
+		if now.Before(entry.expires) {
+			c.order.MoveToFront(element)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return slices.Clone(entry.embedding), true
+		}
+
+		c.order.Remove(element)
+		delete(c.entries, key)
+		c.stats.Expired++
+	}
+	c.mu.Unlock()
+
+	// Disk reads happen outside the lock so memory hits are never blocked on I/O
+	if c.store != nil {
+		embedding, expires, found, err := c.store.get(ctx, key, now)
+		if err != nil {
+			slog.Warn("Failed to read persistent embedding cache", "error", err)
+		}
+		if found {
+			c.mu.Lock()
+			c.insert(key, embedding, expires)
+			c.stats.Hits++
+			c.mu.Unlock()
+			return slices.Clone(embedding), true
+		}
+	}
+
+	c.mu.Lock()
+	c.stats.Misses++
+	c.mu.Unlock()
+	return nil, false
+}
+
+// add caches a copy of a newly generated embedding in memory and on disk
+func (c *CachedLLM) add(ctx context.Context, key string, embedding []float32) {
+	expires := time.Now().Add(c.ttl)
+	embedding = slices.Clone(embedding)
+
+	c.mu.Lock()
+	c.insert(key, embedding, expires)
+	c.mu.Unlock()
+
+	if c.store != nil {
+		if err := c.store.put(ctx, key, embedding, expires, c.maxEntries); err != nil {
+			slog.Warn("Failed to write persistent embedding cache", "error", err)
+		}
+	}
+}
+
+// insert stores an entry as the most recently used, evicting the least recently used
+// beyond maxEntries; callers must hold the lock
+func (c *CachedLLM) insert(key string, embedding []float32, expires time.Time) {
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*cacheEntry)
+		entry.embedding = embedding
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, embedding: embedding, expires: expires})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.stats.Evictions++
+	}
+}
+
+// cacheStore persists cached embeddings in a SQLite table
+type cacheStore struct {
+	db     *sql.DB
+	mu     sync.Mutex
+	writes int // Writes since the last prune
+}
+
+// openCacheStore opens (or creates) the cache database at path
+func openCacheStore(path string) (*cacheStore, error) {
+	dsn := path
+	if !strings.HasPrefix(dsn, "file:") {
+		if dir := filepath.Dir(dsn); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create embedding cache directory: %w", err)
+			}
+		}
+		dsn = fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)", dsn)
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding cache: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	const schema = `CREATE TABLE IF NOT EXISTS embedding_cache (
+		key        TEXT PRIMARY KEY,
+		embedding  BLOB NOT NULL,
+		expires_at INTEGER NOT NULL
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create embedding cache table: %w", err)
+	}
+
+	return &cacheStore{db: db}, nil
+}
+
+// get reads an unexpired embedding
+func (s *cacheStore) get(ctx context.Context, key string, now time.Time) ([]float32, time.Time, bool, error) {
+	var blob []byte
+	var expiresAt int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT embedding, expires_at FROM embedding_cache WHERE key = ? AND expires_at > ?`,
+		key, now.UnixNano()).Scan(&blob, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, false, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, false, fmt.Errorf("failed to read cached embedding: %w", err)
+	}
+
+	return decodeCachedEmbedding(blob), time.Unix(0, expiresAt), true, nil
+}
+
+// put writes an embedding and periodically trims the table back to maxEntries
+func (s *cacheStore) put(ctx context.Context, key string, embedding []float32, expires time.Time, maxEntries int) error {
+	if _, err := s.db.ExecContext(ctx,
+		`INSERT OR REPLACE INTO embedding_cache (key, embedding, expires_at) VALUES (?, ?, ?)`,
+		key, encodeCachedEmbedding(embedding), expires.UnixNano()); err != nil {
+		return fmt.Errorf("failed to write cached embedding: %w", err)
+	}
+
+	s.mu.Lock()
+	s.writes++
+	due := s.writes >= cachePruneInterval
+	if due {
+		s.writes = 0
+	}
+	s.mu.Unlock()
+
+	if due {
+		return s.prune(ctx, time.Now(), maxEntries)
+	}
+	return nil
+}
+
+// prune deletes expired rows and the oldest rows beyond maxEntries
+func (s *cacheStore) prune(ctx context.Context, now time.Time, maxEntries int) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM embedding_cache WHERE expires_at <= ?`, now.UnixNano()); err != nil {
+		return fmt.Errorf("failed to remove expired embeddings: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx,
+		`DELETE FROM embedding_cache WHERE key NOT IN (
+			SELECT key FROM embedding_cache ORDER BY expires_at DESC LIMIT ?
+		)`, maxEntries); err != nil {
+		return fmt.Errorf("failed to trim embedding cache: %w", err)
+	}
+
+	return nil
+}
+
+// clear deletes every row
+func (s *cacheStore) clear(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM embedding_cache`); err != nil {
+		return fmt.Errorf("failed to clear embedding cache: %w", err)
+	}
+	return nil
+}
+
+// close closes the database
+func (s *cacheStore) close() error {
+	return s.db.Close()
+}
+
+// encodeCachedEmbedding packs an embedding as little-endian float32s
+func encodeCachedEmbedding(embedding []float32) []byte {
+	blob := make([]byte, 4*len(embedding))
+	for i, value := range embedding {
+		binary.LittleEndian.PutUint32(blob[i*4:], math.Float32bits(value))
+	}
+	return blob
+}
+
+// decodeCachedEmbedding unpacks an embedding written by encodeCachedEmbedding
+func decodeCachedEmbedding(blob []byte) []float32 {
+	embedding := make([]float32, len(blob)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:]))
+	}
+	return embedding
+}
@@ -0,0 +1,208 @@
+package llm
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+)
+
+// countingLLM embeds with the local provider and records every text it is asked to embed
+type countingLLM struct {
+	*LocalLLM
+	mu       sync.Mutex
+	embedded []string
+}
+
+func (c *countingLLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	c.mu.Lock()
+	c.embedded = append(c.embedded, text)
+	c.mu.Unlock()
+	return c.LocalLLM.GenerateEmbedding(ctx, text)
+}
+
+func (c *countingLLM) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	c.mu.Lock()
+	c.embedded = append(c.embedded, texts...)
+	c.mu.Unlock()
+
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = c.LocalLLM.GenerateEmbedding(ctx, text)
+	}
+	return embeddings, nil
+}
+
+// calls returns the texts embedded since the last call and resets the record
+func (c *countingLLM) calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	embedded := c.embedded
+	c.embedded = nil
+	return embedded
+}
+
+// testCacheConfig configures a local provider with a cache of maxEntries
+func testCacheConfig(maxEntries int, ttl time.Duration, path string) *config.LLMConfig {
+	return &config.LLMConfig{
+		Provider:        "local",
+		Dimension:       8,
+		CacheEnabled:    true,
+		CacheTTL:        ttl,
+		CacheMaxEntries: maxEntries,
+		CachePath:       path,
+	}
+}
+
+// newTestCache wraps a counting local provider in a cache
+func newTestCache(t *testing.T, cfg *config.LLMConfig) (*CachedLLM, *countingLLM) {
+	t.Helper()
+
+	local, err := NewLocalLLM(cfg)
+	if err != nil {
+		t.Fatalf("failed to create local LLM: %v", err)
+	}
+	inner := &countingLLM{LocalLLM: local}
+
+	cache, err := NewCachedLLM(inner, cfg)
+	if err != nil {
+		t.Fatalf("failed to create cache: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+
+	return cache, inner
+}
+
+// embed generates embeddings for texts one at a time
+func embed(t *testing.T, cache *CachedLLM, texts ...string) {
+	t.Helper()
+	for _, text := range texts {
+		if _, err := cache.GenerateEmbedding(context.Background(), text); err != nil {
+			t.Fatalf("failed to embed %q: %v", text, err)
+		}
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	cache, inner := newTestCache(t, testCacheConfig(10, 20*time.Millisecond, ""))
+
+	embed(t, cache, "note", "note")
+	if calls := inner.calls(); !slices.Equal(calls, []string{"note"}) {
+		t.Fatalf("embedded %v before expiry, want one call", calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	embed(t, cache, "note")
+	if calls := inner.calls(); !slices.Equal(calls, []string{"note"}) {
+		t.Errorf("embedded %v after expiry, want the text embedded again", calls)
+	}
+	if stats := cache.Stats(); stats.Expired != 1 {
+		t.Errorf("counted %d expired entries, want 1", stats.Expired)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, inner := newTestCache(t, testCacheConfig(2, time.Hour, ""))
+
+	// Touching "a" leaves "b" as the least recently used when "c" arrives
+	embed(t, cache, "a", "b", "a", "c")
+	inner.calls()
+
+	embed(t, cache, "a", "c")
+	if calls := inner.calls(); len(calls) != 0 {
+		t.Errorf("embedded %v, want the recently used texts cached", calls)
+	}
+	embed(t, cache, "b")
+	if calls := inner.calls(); !slices.Equal(calls, []string{"b"}) {
+		t.Errorf("embedded %v, want the evicted text embedded again", calls)
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 2 {
+		t.Errorf("cache holds %d entries after %d evictions, want 2 after 2", stats.Entries, stats.Evictions)
+	}
+}
+
+func TestCacheReloadsPersistentEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	cache, inner := newTestCache(t, testCacheConfig(10, time.Hour, path))
+	embed(t, cache, "kept across restarts")
+	want, _ := inner.LocalLLM.GenerateEmbedding(context.Background(), "kept across restarts")
+	if err := cache.Close(); err != nil {
+		t.Fatalf("failed to close cache: %v", err)
+	}
+
+	restarted, inner := newTestCache(t, testCacheConfig(10, time.Hour, path))
+	got, err := restarted.GenerateEmbedding(context.Background(), "kept across restarts")
+	if err != nil {
+		t.Fatalf("failed to embed: %v", err)
+	}
+	if calls := inner.calls(); len(calls) != 0 {
+		t.Errorf("embedded %v after a restart, want the persisted embedding", calls)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("reloaded embedding %v, want %v", got, want)
+	}
+
+	// A different dimension is a different namespace, even with the same file
+	resized := testCacheConfig(10, time.Hour, path)
+	resized.Dimension = 16
+	other, inner := newTestCache(t, resized)
+	got, err = other.GenerateEmbedding(context.Background(), "kept across restarts")
+	if err != nil {
+		t.Fatalf("failed to embed: %v", err)
+	}
+	if calls := inner.calls(); len(calls) != 1 || len(got) != 16 {
+		t.Errorf("embedded %v into %d dimensions, want a fresh 16 dimension embedding", calls, len(got))
+	}
+}
+
+func TestCacheBatchEmbedsDuplicatesOnce(t *testing.T) {
+	cache, inner := newTestCache(t, testCacheConfig(10, time.Hour, ""))
+	embed(t, cache, "cached")
+	inner.calls()
+
+	embeddings, err := cache.GenerateEmbeddings(context.Background(), []string{"repeated", "cached", "repeated", "new"})
+	if err != nil {
+		t.Fatalf("failed to embed batch: %v", err)
+	}
+	if calls := inner.calls(); !slices.Equal(calls, []string{"repeated", "new"}) {
+		t.Errorf("embedded %v, want each uncached text once", calls)
+	}
+	if !slices.Equal(embeddings[0], embeddings[2]) {
+		t.Errorf("repeated text embedded as %v and %v", embeddings[0], embeddings[2])
+	}
+
+	// Each position is its own copy
+	embeddings[0][0] = 42
+	if embeddings[2][0] == 42 {
+		t.Error("repeated texts share one embedding slice")
+	}
+}
+
+func TestCacheReturnsCopies(t *testing.T) {
+	cache, _ := newTestCache(t, testCacheConfig(10, time.Hour, ""))
+	ctx := context.Background()
+
+	generated, err := cache.GenerateEmbedding(ctx, "note")
+	if err != nil {
+		t.Fatalf("failed to embed: %v", err)
+	}
+	want := slices.Clone(generated)
+
+	// Callers that normalize or scale their vectors must not change the cached one
+	generated[0] = 42
+	hit, _ := cache.GenerateEmbedding(ctx, "note")
+	hit[1] = 42
+
+	again, _ := cache.GenerateEmbedding(ctx, "note")
+	if !slices.Equal(again, want) {
+		t.Errorf("cached embedding changed to %v, want %v", again, want)
+	}
+}
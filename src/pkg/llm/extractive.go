@@ -0,0 +1,94 @@
+package llm
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// extractiveSentences caps how many sentences an extractive summary keeps
+const extractiveSentences = 5
+
+// sentenceBoundary splits text after sentence punctuation and at line breaks
+var sentenceBoundary = regexp.MustCompile(`(?:[.!?])\s+|\n+`)
+
+// stopWords are common words that carry no topic and are ignored when scoring sentences
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"but": true, "by": true, "for": true, "from": true, "has": true, "have": true, "i": true,
+	"if": true, "in": true, "is": true, "it": true, "its": true, "of": true, "on": true,
+	"or": true, "so": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"we": true, "were": true, "with": true, "you": true,
+}
+
+// ExtractiveSummary consolidates memories without a model by selecting their most
+// representative sentences. Sentences are scored by how often their words occur across
+// all of the memories and the best are returned in their original order.
+func ExtractiveSummary(memories []string) string {
+	type sentence struct {
+		text  string
+		words []string
+		score float64
+		order int
+	}
+
+	var sentences []*sentence
+	seen := make(map[string]bool)
+	frequency := make(map[string]int)
+	for _, memory := range memories {
+		for _, text := range sentenceBoundary.Split(memory, -1) {
+			text = strings.TrimSpace(text)
+			key := strings.ToLower(text)
+			if text == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			words := slices.DeleteFunc(tokenize(text), func(word string) bool {
+				return stopWords[word]
+			})
+			for _, word := range words {
+				frequency[word]++
+			}
+			sentences = append(sentences, &sentence{text: text, words: words, order: len(sentences)})
+		}
+	}
+
+	if len(sentences) == 0 {
+		return ""
+	}
+
+	// Average word frequency favours sentences about the shared topic over long ones
+	for _, s := range sentences {
+		for _, word := range s.words {
+			s.score += float64(frequency[word])
+		}
+		if len(s.words) > 0 {
+			s.score /= float64(len(s.words))
+		}
+	}
+
+	ranked := slices.Clone(sentences)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].score > ranked[j].score
+	})
+	selected := ranked[:min(len(ranked), extractiveSentences)]
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].order < selected[j].order
+	})
+
+	parts := make([]string, len(selected))
+	for i, s := range selected {
+		parts[i] = strings.TrimRight(s.text, ".!?") + "."
+	}
+	return strings.Join(parts, " ")
+}
+
+// tokenize lowercases text and splits it into words of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,222 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// ErrInvalidKnowledge is returned when the model's structured output cannot be parsed or validated
+var ErrInvalidKnowledge = errors.New("invalid knowledge extraction")
+
+// insightTypes lists the accepted metacognitive insight types
+var insightTypes = []string{"learning_strategy", "mistake_pattern", "improvement"}
+
+// rawKnowledge mirrors the JSON the model is asked to produce. Evidence refers to the
+// numbered memories in the prompt rather than to memory IDs, which models copy poorly.
+type rawKnowledge struct {
+	Summary  string `json:"summary"`
+	Concepts []struct {
+		Name       string   `json:"name"`
+		Definition string   `json:"definition"`
+		Examples   []string `json:"examples"`
+		Evidence   []int    `json:"evidence"`
+	} `json:"concepts"`
+	Patterns []struct {
+		Trigger  string   `json:"trigger"`
+		Actions  []string `json:"actions"`
+		Evidence []int    `json:"evidence"`
+	} `json:"patterns"`
+	Insights []struct {
+		Type        string  `json:"type"`
+		Description string  `json:"description"`
+		Confidence  float32 `json:"confidence"`
+		Evidence    []int   `json:"evidence"`
+	} `json:"insights"`
+}
+
+// extractKnowledge asks the model for structured knowledge and validates the answer.
+// Malformed output is repaired where possible; otherwise the model is asked again with
+// the validation error, up to maxRetries times.
+func extractKnowledge(ctx context.Context, memories []*models.MemoryEntry, maxRetries int, generate func(ctx context.Context, prompt string) (string, error)) (*models.Knowledge, error) {
+	prompt := buildKnowledgePrompt(memories)
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		request := prompt
+		if lastErr != nil {
+			request += fmt.Sprintf("\n\nYour previous response was rejected: %v\nRespond again with only the corrected JSON object.", lastErr)
+		}
+
+		response, err := generate(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		knowledge, err := parseKnowledge(response, memories)
+		if err == nil {
+			return knowledge, nil
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("failed after %d attempts: %w", maxRetries+1, lastErr)
+}
+
+// buildKnowledgePrompt creates a prompt asking for the knowledge schema as JSON
+func buildKnowledgePrompt(memories []*models.MemoryEntry) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are a memory consolidation system. Analyze the following episodic memories and extract structured knowledge.\n\n")
+	prompt.WriteString("Episodic memories to analyze:\n")
+	for i, memory := range memories {
+		fmt.Fprintf(&prompt, "%d. %s\n", i+1, memory.Content)
+	}
+
+	prompt.WriteString(`
+Respond with only a JSON object of this form:
+{
+  "summary": "concise consolidated knowledge from all of the memories",
+  "concepts": [{"name": "...", "definition": "...", "examples": ["..."], "evidence": [1, 2]}],
+  "patterns": [{"trigger": "situation that starts the pattern", "actions": ["step", "step"], "evidence": [1]}],
+  "insights": [{"type": "learning_strategy | mistake_pattern | improvement", "description": "...", "confidence": 0.8, "evidence": [2]}]
+}
+Concepts are facts or ideas, patterns are repeatable procedures and insights are observations about how the work went.
+Evidence lists the numbers of the memories that support each entry. Use empty lists when nothing applies.`)
+
+	return prompt.String()
+}
+
+// parseKnowledge decodes and validates a model response, resolving evidence numbers to memory IDs
+func parseKnowledge(response string, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	var raw rawKnowledge
+	if err := json.Unmarshal([]byte(response), &raw); err != nil {
+		if repairErr := json.Unmarshal([]byte(repairJSON(response)), &raw); repairErr != nil {
+			return nil, fmt.Errorf("%w: response is not valid JSON: %v", ErrInvalidKnowledge, err)
+		}
+	}
+
+	knowledge := &models.Knowledge{
+		Summary:  strings.TrimSpace(raw.Summary),
+		Concepts: []*models.Concept{},
+		Patterns: []*models.Pattern{},
+		Insights: []*models.Insight{},
+	}
+	if knowledge.Summary == "" {
+		return nil, fmt.Errorf("%w: summary is required", ErrInvalidKnowledge)
+	}
+
+	for i, concept := range raw.Concepts {
+		evidence, err := resolveEvidence(concept.Evidence, memories)
+		if err != nil {
+			return nil, fmt.Errorf("%w: concept %d: %v", ErrInvalidKnowledge, i+1, err)
+		}
+		if strings.TrimSpace(concept.Name) == "" || strings.TrimSpace(concept.Definition) == "" {
+			return nil, fmt.Errorf("%w: concept %d needs a name and a definition", ErrInvalidKnowledge, i+1)
+		}
+
+		knowledge.Concepts = append(knowledge.Concepts, &models.Concept{
+			Name:        strings.TrimSpace(concept.Name),
+			Definition:  strings.TrimSpace(concept.Definition),
+			Examples:    concept.Examples,
+			CreatedFrom: evidence,
+		})
+	}
+
+	for i, pattern := range raw.Patterns {
+		evidence, err := resolveEvidence(pattern.Evidence, memories)
+		if err != nil {
+			return nil, fmt.Errorf("%w: pattern %d: %v", ErrInvalidKnowledge, i+1, err)
+		}
+
+		actions := make([]string, 0, len(pattern.Actions))
+		for _, action := range pattern.Actions {
+			if action = strings.TrimSpace(action); action != "" {
+				actions = append(actions, action)
+			}
+		}
+		if strings.TrimSpace(pattern.Trigger) == "" || len(actions) == 0 {
+			return nil, fmt.Errorf("%w: pattern %d needs a trigger and at least one action", ErrInvalidKnowledge, i+1)
+		}
+
+		knowledge.Patterns = append(knowledge.Patterns, &models.Pattern{
+			Trigger:  strings.TrimSpace(pattern.Trigger),
+			Actions:  actions,
+			Evidence: evidence,
+		})
+	}
+
+	for i, insight := range raw.Insights {
+		evidence, err := resolveEvidence(insight.Evidence, memories)
+		if err != nil {
+			return nil, fmt.Errorf("%w: insight %d: %v", ErrInvalidKnowledge, i+1, err)
+		}
+
+		insightType := strings.ToLower(strings.TrimSpace(insight.Type))
+		if !slices.Contains(insightTypes, insightType) {
+			return nil, fmt.Errorf("%w: insight %d has type %q, expected one of %s", ErrInvalidKnowledge, i+1, insight.Type, strings.Join(insightTypes, ", "))
+		}
+		if strings.TrimSpace(insight.Description) == "" {
+			return nil, fmt.Errorf("%w: insight %d needs a description", ErrInvalidKnowledge, i+1)
+		}
+		if insight.Confidence < 0 || insight.Confidence > 1 {
+			return nil, fmt.Errorf("%w: insight %d confidence must be between 0 and 1", ErrInvalidKnowledge, i+1)
+		}
+
+		knowledge.Insights = append(knowledge.Insights, &models.Insight{
+			Type:        insightType,
+			Description: strings.TrimSpace(insight.Description),
+			Evidence:    evidence,
+			Confidence:  insight.Confidence,
+		})
+	}
+
+	return knowledge, nil
+}
+
+// resolveEvidence maps 1-based memory numbers to memory IDs, requiring at least one
+func resolveEvidence(numbers []int, memories []*models.MemoryEntry) ([]string, error) {
+	if len(numbers) == 0 {
+		return nil, fmt.Errorf("evidence must cite at least one memory")
+	}
+
+	ids := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		if number < 1 || number > len(memories) {
+			return nil, fmt.Errorf("evidence %d does not match a memory (1-%d)", number, len(memories))
+		}
+		if id := memories[number-1].ID; !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+var (
+	// codeFencePattern matches a Markdown code fence around the response
+	codeFencePattern = regexp.MustCompile("(?s)^```[a-zA-Z]*\\s*(.*?)\\s*```$")
+
+	// trailingCommaPattern matches a comma directly before a closing bracket
+	trailingCommaPattern = regexp.MustCompile(`,\s*([}\]])`)
+)
+
+// repairJSON fixes common model formatting mistakes: code fences, prose around the
+// object and trailing commas
+func repairJSON(response string) string {
+	repaired := strings.TrimSpace(response)
+	if match := codeFencePattern.FindStringSubmatch(repaired); match != nil {
+		repaired = match[1]
+	}
+
+	if start, end := strings.Index(repaired, "{"), strings.LastIndex(repaired, "}"); start >= 0 && end > start {
+		repaired = repaired[start : end+1]
+	}
+
+	return trailingCommaPattern.ReplaceAllString(repaired, "$1")
+}
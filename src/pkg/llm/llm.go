@@ -2,9 +2,12 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
 )
 
 // LLM defines the interface for Large Language Model operations
@@ -12,9 +15,16 @@ type LLM interface {
 	// GenerateEmbedding creates vector embeddings for the given text
 	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
 
+	// GenerateEmbeddings creates vector embeddings for several texts, returned in input order
+	GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error)
+
 	// ConsolidateMemories uses the LLM to consolidate multiple memories into semantic knowledge
 	ConsolidateMemories(ctx context.Context, memories []string) (string, error)
 
+	// ExtractKnowledge consolidates memories into a summary plus concepts, patterns and
+	// insights whose evidence cites the given memories' IDs
+	ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error)
+
 	// HealthCheck verifies the LLM service is accessible
 	HealthCheck(ctx context.Context) error
 
@@ -22,16 +32,108 @@ type LLM interface {
 	ClearCache()
 }
 
-// NewLLM creates a new LLM implementation based on the provider
+// errBatchUnsupported is returned when a server has no batch embedding endpoint
+var errBatchUnsupported = errors.New("batch embedding not supported")
+
+// embeddingBatchSize caps how many texts are sent in one batch embedding request
+const embeddingBatchSize = 64
+
+// embeddingConcurrency caps parallel single-text requests when a server has no batch input
+const embeddingConcurrency = 4
+
+// NewLLM creates a new LLM implementation based on the provider, wrapped in a
+// CachedLLM when the embedding cache is enabled
 func NewLLM(config *config.LLMConfig) (LLM, error) {
+	provider, err := newProvider(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Local embeddings are cheaper to compute than to look up
+	if !config.CacheEnabled || config.Provider == "local" {
+		return provider, nil
+	}
+	return NewCachedLLM(provider, config)
+}
+
+// newProvider creates the LLM implementation for the configured provider
+func newProvider(config *config.LLMConfig) (LLM, error) {
 	switch config.Provider {
 	case "ollama":
 		return NewOllamaLLM(config)
-	case "openai":
-		return nil, fmt.Errorf("OpenAI provider not yet implemented")
-	case "localai":
-		return nil, fmt.Errorf("LocalAI provider not yet implemented")
+	case "openai", "localai":
+		return NewOpenAILLM(config)
+	case "local":
+		return NewLocalLLM(config)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
 }
+
+// buildConsolidationPrompt creates a prompt for memory consolidation
+func buildConsolidationPrompt(memories []string) string {
+	prompt := "You are a memory consolidation system. Analyze the following episodic memories and extract the key semantic knowledge, patterns, and insights. "
+	prompt += "Consolidate them into concise, meaningful knowledge that can be stored as semantic memory.\n\n"
+	prompt += "Episodic memories to analyze:\n"
+
+	for i, memory := range memories {
+		prompt += fmt.Sprintf("%d. %s\n", i+1, memory)
+	}
+
+	prompt += "\nPlease provide a consolidated summary that captures the essential knowledge and patterns from these memories:"
+
+	return prompt
+}
+
+// embedConcurrently embeds texts one request at a time with bounded concurrency, for
+// servers that cannot embed a batch in a single request
+func embedConcurrently(ctx context.Context, texts []string, embed func(ctx context.Context, text string) ([]float32, error)) ([][]float32, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	embeddings := make([][]float32, len(texts))
+	errs := make([]error, len(texts))
+	sem := make(chan struct{}, embeddingConcurrency)
+	var wg sync.WaitGroup
+
+	for i, text := range texts {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			embeddings[i], errs[i] = embed(ctx, text)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed text %d of %d: %w", i+1, len(texts), err)
+		}
+	}
+	return embeddings, nil
+}
+
+// embedInBatches splits texts into chunks of embeddingBatchSize and embeds each chunk
+// with a single request, checking that every text received an embedding
+func embedInBatches(ctx context.Context, texts []string, embed func(ctx context.Context, batch []string) ([][]float32, error)) ([][]float32, error) {
+	embeddings := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingBatchSize {
+		batch := texts[start:min(start+embeddingBatchSize, len(texts))]
+
+		batchEmbeddings, err := embed(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		if len(batchEmbeddings) != len(batch) {
+			return nil, fmt.Errorf("expected %d embeddings, got %d", len(batch), len(batchEmbeddings))
+		}
+		embeddings = append(embeddings, batchEmbeddings...)
+	}
+	return embeddings, nil
+}
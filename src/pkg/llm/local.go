@@ -0,0 +1,116 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// bigramWeight scales word pairs relative to single words in local embeddings
+const bigramWeight = 0.5
+
+// LocalLLM implements LLM operations without a model server. Embeddings are built by
+// feature hashing over word n-grams and consolidation is extractive, so results are
+// deterministic and available offline at the cost of semantic quality.
+type LocalLLM struct {
+	config *config.LLMConfig
+}
+
+// NewLocalLLM creates a new local LLM implementation
+func NewLocalLLM(config *config.LLMConfig) (*LocalLLM, error) {
+	if config.Dimension <= 0 {
+		return nil, fmt.Errorf("local provider requires a positive embedding dimension")
+	}
+
+	return &LocalLLM{config: config}, nil
+}
+
+// GenerateEmbedding hashes the text's words and word pairs into a normalized vector
+func (c *LocalLLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	embedding := make([]float32, c.config.Dimension)
+
+	words := tokenize(text)
+	for i, word := range words {
+		c.addFeature(embedding, word, 1)
+		if i > 0 {
+			c.addFeature(embedding, words[i-1]+" "+word, bigramWeight)
+		}
+	}
+
+	var norm float64
+	for _, value := range embedding {
+		norm += float64(value) * float64(value)
+	}
+	if norm > 0 {
+		scale := float32(1 / math.Sqrt(norm))
+		for i := range embedding {
+			embedding[i] *= scale
+		}
+	}
+
+	return embedding, nil
+}
+
+// GenerateEmbeddings embeds each text locally; there is no request overhead to batch away
+func (c *LocalLLM) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	embeddings := make([][]float32, len(texts))
+	for i, text := range texts {
+		embeddings[i], _ = c.GenerateEmbedding(ctx, text)
+	}
+	return embeddings, nil
+}
+
+// addFeature adds weight to the dimension a feature hashes to. A second hash bit picks
+// the sign so colliding features tend to cancel rather than accumulate.
+func (c *LocalLLM) addFeature(embedding []float32, feature string, weight float32) {
+	hash := fnv.New64a()
+	hash.Write([]byte(feature))
+	sum := hash.Sum64()
+
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	embedding[sum%uint64(len(embedding))] += weight
+}
+
+// ConsolidateMemories summarizes memories by extracting their most representative sentences
+func (c *LocalLLM) ConsolidateMemories(ctx context.Context, memories []string) (string, error) {
+	summary := ExtractiveSummary(memories)
+	if summary == "" {
+		return "", fmt.Errorf("no content to consolidate")
+	}
+	return summary, nil
+}
+
+// ExtractKnowledge returns an extractive summary; the local provider does not identify
+// concepts, patterns or insights
+func (c *LocalLLM) ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	texts := make([]string, len(memories))
+	for i, memory := range memories {
+		texts[i] = memory.Content
+	}
+
+	summary, err := c.ConsolidateMemories(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Knowledge{
+		Summary:  summary,
+		Concepts: []*models.Concept{},
+		Patterns: []*models.Pattern{},
+		Insights: []*models.Insight{},
+	}, nil
+}
+
+// HealthCheck always succeeds because the local provider has no dependencies
+func (c *LocalLLM) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// ClearCache is a no-op; local embeddings are cheaper to compute than to cache
+func (c *LocalLLM) ClearCache() {}
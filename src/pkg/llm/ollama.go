@@ -4,18 +4,23 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
 )
 
 // OllamaLLM implements LLM operations using Ollama
 type OllamaLLM struct {
-	config *config.LLMConfig
-	client *http.Client
-	cache  map[string][]float32
+	config  *config.LLMConfig
+	client  *http.Client
+	noBatch atomic.Bool // Set once the server is found to lack /api/embed
 }
 
 // EmbeddingRequest represents a request to generate embeddings
@@ -29,11 +34,23 @@ type EmbeddingResponse struct {
 	Embedding []float32 `json:"embedding"`
 }
 
+// BatchEmbeddingRequest represents a request to embed several inputs with /api/embed
+type BatchEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// BatchEmbeddingResponse represents the response from /api/embed
+type BatchEmbeddingResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
 // GenerateRequest represents a request for text generation
 type GenerateRequest struct {
 	Model  string `json:"model"`
 	Prompt string `json:"prompt"`
 	Stream bool   `json:"stream"`
+	Format string `json:"format,omitempty"` // "json" constrains the response to valid JSON
 }
 
 // GenerateResponse represents the response from text generation
@@ -51,19 +68,11 @@ func NewOllamaLLM(config *config.LLMConfig) (*OllamaLLM, error) {
 		client: &http.Client{
 			Timeout: config.Timeout,
 		},
-		cache: make(map[string][]float32),
 	}, nil
 }
 
 // GenerateEmbedding generates embeddings for the given text
 func (c *OllamaLLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
-	// Check cache first if enabled
-	if c.config.CacheEnabled {
-		if embedding, exists := c.cache[text]; exists {
-			return embedding, nil
-		}
-	}
-
 	// Prepare request
 	reqBody := EmbeddingRequest{
 		Model:  c.config.EmbeddingModel,
@@ -99,11 +108,6 @@ func (c *OllamaLLM) GenerateEmbedding(ctx context.Context, text string) ([]float
 		return nil, fmt.Errorf("failed after %d attempts: %w", c.config.MaxRetries+1, lastErr)
 	}
 
-	// Cache the result if enabled
-	if c.config.CacheEnabled {
-		c.cache[text] = embedding
-	}
-
 	return embedding, nil
 }
 
@@ -136,14 +140,137 @@ func (c *OllamaLLM) makeEmbeddingRequest(ctx context.Context, jsonData []byte) (
 	return embeddingResp.Embedding, nil
 }
 
+// GenerateEmbeddings embeds texts with /api/embed batch requests, falling back to
+// concurrent single requests on Ollama versions without that endpoint
+func (c *OllamaLLM) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	var embeddings [][]float32
+	err := errBatchUnsupported
+	if !c.noBatch.Load() {
+		embeddings, err = embedInBatches(ctx, texts, c.embedBatch)
+	}
+	if errors.Is(err, errBatchUnsupported) {
+		if c.noBatch.CompareAndSwap(false, true) {
+			slog.Warn("Ollama has no batch embedding endpoint, embedding texts individually")
+		}
+		embeddings, err = embedConcurrently(ctx, texts, c.GenerateEmbedding)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// embedBatch embeds one batch of texts with retries
+func (c *OllamaLLM) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	reqBody := BatchEmbeddingRequest{
+		Model: c.config.EmbeddingModel,
+		Input: texts,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Make request with retries
+	var embeddings [][]float32
+	var lastErr error
+
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		embeddings, lastErr = c.makeBatchEmbeddingRequest(ctx, jsonData)
+		if lastErr == nil || errors.Is(lastErr, errBatchUnsupported) {
+			break
+		}
+
+		if attempt < c.config.MaxRetries {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt+1) * time.Second):
+				// Exponential backoff
+			}
+		}
+	}
+
+	if errors.Is(lastErr, errBatchUnsupported) {
+		return nil, lastErr
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("failed after %d attempts: %w", c.config.MaxRetries+1, lastErr)
+	}
+
+	return embeddings, nil
+}
+
+// makeBatchEmbeddingRequest makes a single /api/embed request
+func (c *OllamaLLM) makeBatchEmbeddingRequest(ctx context.Context, jsonData []byte) ([][]float32, error) {
+	url := c.config.URL + "/api/embed"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		// A missing model is also a 404, but only it comes with an Ollama error message
+		if message := ollamaError(resp.Body); message != "" {
+			return nil, fmt.Errorf("request failed with status %d: %s", resp.StatusCode, message)
+		}
+		return nil, errBatchUnsupported
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	var embeddingResp BatchEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embeddingResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return embeddingResp.Embeddings, nil
+}
+
+// ollamaError reads the message of an Ollama JSON error body, or returns an empty string
+// when the body is not one
+func ollamaError(body io.Reader) string {
+	var payload struct {
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(body, 4096))
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return ""
+	}
+	return payload.Error
+}
+
 // ConsolidateMemories uses the LLM to consolidate multiple memories into semantic knowledge
 func (c *OllamaLLM) ConsolidateMemories(ctx context.Context, memories []string) (string, error) {
-	prompt := c.buildConsolidationPrompt(memories)
+	return c.generate(ctx, buildConsolidationPrompt(memories), "")
+}
+
+// ExtractKnowledge asks the LLM for a summary plus concepts, patterns and insights as JSON
+func (c *OllamaLLM) ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	return extractKnowledge(ctx, memories, c.config.MaxRetries, func(ctx context.Context, prompt string) (string, error) {
+		return c.generate(ctx, prompt, "json")
+	})
+}
 
+// generate runs a prompt against the consolidation model, retrying failed requests
+func (c *OllamaLLM) generate(ctx context.Context, prompt string, format string) (string, error) {
 	reqBody := GenerateRequest{
 		Model:  c.config.ConsolidationModel,
 		Prompt: prompt,
 		Stream: false,
+		Format: format,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -207,21 +334,6 @@ func (c *OllamaLLM) makeGenerateRequest(ctx context.Context, jsonData []byte) (s
 	return generateResp.Response, nil
 }
 
-// buildConsolidationPrompt creates a prompt for memory consolidation
-func (c *OllamaLLM) buildConsolidationPrompt(memories []string) string {
-	prompt := "You are a memory consolidation system. Analyze the following episodic memories and extract the key semantic knowledge, patterns, and insights. "
-	prompt += "Consolidate them into concise, meaningful knowledge that can be stored as semantic memory.\n\n"
-	prompt += "Episodic memories to analyze:\n"
-
-	for i, memory := range memories {
-		prompt += fmt.Sprintf("%d. %s\n", i+1, memory)
-	}
-
-	prompt += "\nPlease provide a consolidated summary that captures the essential knowledge and patterns from these memories:"
-
-	return prompt
-}
-
 // HealthCheck checks if Ollama is accessible
 func (c *OllamaLLM) HealthCheck(ctx context.Context) error {
 	url := c.config.URL + "/api/tags"
@@ -244,9 +356,5 @@ func (c *OllamaLLM) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
-// ClearCache clears the embedding cache
-func (c *OllamaLLM) ClearCache() {
-	if c.config.CacheEnabled {
-		c.cache = make(map[string][]float32)
-	}
-}
+// ClearCache is a no-op; embeddings are cached by wrapping the provider in a CachedLLM
+func (c *OllamaLLM) ClearCache() {}
@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+)
+
+// newTestOllama returns a client for an httptest server running handler
+func newTestOllama(t *testing.T, handler http.HandlerFunc) *OllamaLLM {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewOllamaLLM(&config.LLMConfig{
+		Provider:       "ollama",
+		URL:            server.URL,
+		EmbeddingModel: "test-embed",
+		Timeout:        5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestOllamaFallsBackWithoutBatchEndpoint(t *testing.T) {
+	client := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/embed" {
+			http.NotFound(w, r) // Older Ollama versions have no such route
+			return
+		}
+
+		var req EmbeddingRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		json.NewEncoder(w).Encode(EmbeddingResponse{Embedding: []float32{float32(len(req.Prompt))}})
+	})
+
+	embeddings, err := client.GenerateEmbeddings(context.Background(), []string{"a", "bb"})
+	if err != nil {
+		t.Fatalf("failed to generate embeddings: %v", err)
+	}
+	if embeddings[0][0] != 1 || embeddings[1][0] != 2 {
+		t.Errorf("embeddings are %v, want them in input order", embeddings)
+	}
+	if !client.noBatch.Load() {
+		t.Error("batch endpoint still in use after it was not found")
+	}
+}
+
+func TestOllamaMissingModelKeepsBatchEndpoint(t *testing.T) {
+	client := newTestOllama(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": `model "test-embed" not found, try pulling it first`})
+	})
+
+	_, err := client.GenerateEmbeddings(context.Background(), []string{"a", "bb"})
+	if err == nil || !strings.Contains(err.Error(), "not found, try pulling it first") {
+		t.Fatalf("error is %v, want the missing model message", err)
+	}
+	if client.noBatch.Load() {
+		t.Error("batch endpoint disabled because the model was missing")
+	}
+}
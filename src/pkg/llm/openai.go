@@ -0,0 +1,347 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// OpenAILLM implements LLM operations against the OpenAI /v1/embeddings and
+// /v1/chat/completions APIs, which OpenAI, LocalAI, vLLM and llama.cpp servers share
+type OpenAILLM struct {
+	config  *config.LLMConfig
+	client  *http.Client
+	baseURL string        // Server root without the /v1 suffix
+	apiKey  string        // Bearer token, empty for servers without authentication
+	backoff time.Duration // Delay before the first retry, doubled on each attempt
+	noBatch atomic.Bool   // Set once the server is found to reject array input
+}
+
+// ChatMessage is a single message in a chat completion conversation
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ResponseFormat constrains the shape of a chat completion
+type ResponseFormat struct {
+	Type string `json:"type"` // "text" or "json_object"
+}
+
+// ChatCompletionRequest represents a request to /v1/chat/completions
+type ChatCompletionRequest struct {
+	Model          string          `json:"model"`
+	Messages       []ChatMessage   `json:"messages"`
+	Stream         bool            `json:"stream"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ChatCompletionResponse represents the response from /v1/chat/completions
+type ChatCompletionResponse struct {
+	Choices []struct {
+		Message      ChatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// OpenAIEmbeddingRequest represents a request to /v1/embeddings
+type OpenAIEmbeddingRequest struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+// OpenAIBatchEmbeddingRequest represents a request to /v1/embeddings with array input
+type OpenAIBatchEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// OpenAIEmbeddingResponse represents the response from /v1/embeddings
+type OpenAIEmbeddingResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+// APIError is a non-success response from an OpenAI-compatible server
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration // Server-requested delay before retrying, if any
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request failed with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed if sent again
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// NewOpenAILLM creates a new OpenAI-compatible LLM implementation. The API key is read
+// from the environment variable named by the api_key_env setting.
+func NewOpenAILLM(config *config.LLMConfig) (*OpenAILLM, error) {
+	var apiKey string
+	if config.APIKeyEnv != "" {
+		apiKey = os.Getenv(config.APIKeyEnv)
+	}
+	if apiKey == "" && config.Provider == "openai" {
+		slog.Warn("No API key found for the OpenAI provider, requests will be unauthenticated", "env", config.APIKeyEnv)
+	}
+
+	return &OpenAILLM{
+		config: config,
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		baseURL: strings.TrimSuffix(strings.TrimRight(config.URL, "/"), "/v1"),
+		apiKey:  apiKey,
+		backoff: time.Second,
+	}, nil
+}
+
+// GenerateEmbedding generates embeddings for the given text
+func (c *OpenAILLM) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	reqBody := OpenAIEmbeddingRequest{
+		Model: c.config.EmbeddingModel,
+		Input: text,
+	}
+
+	var embeddingResp OpenAIEmbeddingResponse
+	if err := c.post(ctx, "/v1/embeddings", reqBody, &embeddingResp); err != nil {
+		return nil, err
+	}
+	if len(embeddingResp.Data) == 0 {
+		return nil, fmt.Errorf("embedding response contained no data")
+	}
+	embedding := embeddingResp.Data[0].Embedding
+
+	return embedding, nil
+}
+
+// GenerateEmbeddings embeds texts with array input requests, falling back to concurrent
+// single requests on servers that reject array input. A rejected batch is retried one
+// text at a time; only if every text then succeeds is array input switched off, since
+// otherwise a single bad input, such as one over the model's length limit, was at fault.
+func (c *OpenAILLM) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if c.noBatch.Load() {
+		return embedConcurrently(ctx, texts, c.GenerateEmbedding)
+	}
+
+	embeddings, err := embedInBatches(ctx, texts, c.embedBatch)
+	if errors.Is(err, errBatchUnsupported) {
+		batchErr := err
+		embeddings, err = embedConcurrently(ctx, texts, c.GenerateEmbedding)
+		if err == nil && c.noBatch.CompareAndSwap(false, true) {
+			slog.Warn("Server rejected array embedding input, embedding texts individually", "error", batchErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return embeddings, nil
+}
+
+// embedBatch embeds one batch of texts with a single array input request. Client errors
+// other than authentication failures are reported as errBatchUnsupported, which
+// GenerateEmbeddings confirms by embedding the texts individually.
+func (c *OpenAILLM) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	reqBody := OpenAIBatchEmbeddingRequest{
+		Model: c.config.EmbeddingModel,
+		Input: texts,
+	}
+
+	var embeddingResp OpenAIEmbeddingResponse
+	if err := c.post(ctx, "/v1/embeddings", reqBody, &embeddingResp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.Retryable() &&
+			apiErr.StatusCode != http.StatusUnauthorized && apiErr.StatusCode != http.StatusForbidden {
+			return nil, fmt.Errorf("%w: %v", errBatchUnsupported, err)
+		}
+		return nil, err
+	}
+
+	// Data is not guaranteed to be in input order
+	sort.Slice(embeddingResp.Data, func(i, j int) bool {
+		return embeddingResp.Data[i].Index < embeddingResp.Data[j].Index
+	})
+
+	embeddings := make([][]float32, len(embeddingResp.Data))
+	for i, data := range embeddingResp.Data {
+		embeddings[i] = data.Embedding
+	}
+	return embeddings, nil
+}
+
+// ConsolidateMemories uses the LLM to consolidate multiple memories into semantic knowledge
+func (c *OpenAILLM) ConsolidateMemories(ctx context.Context, memories []string) (string, error) {
+	return c.complete(ctx, buildConsolidationPrompt(memories), nil)
+}
+
+// ExtractKnowledge asks the LLM for a summary plus concepts, patterns and insights as JSON
+func (c *OpenAILLM) ExtractKnowledge(ctx context.Context, memories []*models.MemoryEntry) (*models.Knowledge, error) {
+	return extractKnowledge(ctx, memories, c.config.MaxRetries, func(ctx context.Context, prompt string) (string, error) {
+		return c.complete(ctx, prompt, &ResponseFormat{Type: "json_object"})
+	})
+}
+
+// complete runs a prompt against the consolidation model as a single user message
+func (c *OpenAILLM) complete(ctx context.Context, prompt string, format *ResponseFormat) (string, error) {
+	reqBody := ChatCompletionRequest{
+		Model:          c.config.ConsolidationModel,
+		Messages:       []ChatMessage{{Role: "user", Content: prompt}},
+		Stream:         false,
+		ResponseFormat: format,
+	}
+
+	var completion ChatCompletionResponse
+	if err := c.post(ctx, "/v1/chat/completions", reqBody, &completion); err != nil {
+		return "", err
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("completion response contained no choices")
+	}
+
+	return completion.Choices[0].Message.Content, nil
+}
+
+// post sends a JSON request, retrying with exponential backoff on rate limits, server
+// errors and transport failures. Other client errors are returned immediately.
+func (c *OpenAILLM) post(ctx context.Context, path string, reqBody any, out any) error {
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		lastErr = c.makeRequest(ctx, path, jsonData, out)
+		if lastErr == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		isAPIErr := errors.As(lastErr, &apiErr)
+		if isAPIErr && !apiErr.Retryable() {
+			return lastErr
+		}
+
+		if attempt < c.config.MaxRetries {
+			delay := c.backoff << attempt
+			if isAPIErr && apiErr.RetryAfter > 0 {
+				delay = apiErr.RetryAfter
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	return fmt.Errorf("failed after %d attempts: %w", c.config.MaxRetries+1, lastErr)
+}
+
+// makeRequest makes a single request and decodes a successful response into out
+func (c *OpenAILLM) makeRequest(ctx context.Context, path string, jsonData []byte, out any) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// newAPIError builds an APIError from a failed response, reading the OpenAI error
+// message and Retry-After header when present
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var payload struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error.Message != "" {
+		apiErr.Message = payload.Error.Message
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return apiErr
+}
+
+// authorize adds the bearer token when an API key is configured
+func (c *OpenAILLM) authorize(req *http.Request) {
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+}
+
+// HealthCheck checks that the server is accessible and accepts the API key
+func (c *OpenAILLM) HealthCheck(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/v1/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health check request: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: %w", newAPIError(resp))
+	}
+
+	return nil
+}
+
+// ClearCache is a no-op; embeddings are cached by wrapping the provider in a CachedLLM
+func (c *OpenAILLM) ClearCache() {}
@@ -0,0 +1,307 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+)
+
+// testAPIKeyEnv names the environment variable the test clients read their key from
+const testAPIKeyEnv = "PERSISTENT_CONTEXT_TEST_OPENAI_KEY"
+
+// newTestOpenAI returns a client for an httptest server running handler. The key is
+// exported for the client to read; an empty key leaves requests unauthenticated.
+func newTestOpenAI(t *testing.T, key string, handler http.HandlerFunc) *OpenAILLM {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv(testAPIKeyEnv, key)
+
+	client, err := NewOpenAILLM(&config.LLMConfig{
+		Provider:           "openai",
+		URL:                server.URL + "/v1",
+		APIKeyEnv:          testAPIKeyEnv,
+		EmbeddingModel:     "test-embed",
+		ConsolidationModel: "test-chat",
+		Timeout:            5 * time.Second,
+		MaxRetries:         2,
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	client.backoff = time.Millisecond
+	return client
+}
+
+// embeddingInput decodes the input of an embeddings request, which is a string or an array
+func embeddingInput(t *testing.T, r *http.Request) (model string, texts []string, batch bool) {
+	t.Helper()
+
+	var req struct {
+		Model string          `json:"model"`
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		t.Errorf("failed to decode request: %v", err)
+		return "", nil, false
+	}
+
+	if strings.HasPrefix(string(req.Input), "[") {
+		if err := json.Unmarshal(req.Input, &texts); err != nil {
+			t.Errorf("failed to decode array input: %v", err)
+		}
+		return req.Model, texts, true
+	}
+
+	var text string
+	if err := json.Unmarshal(req.Input, &text); err != nil {
+		t.Errorf("failed to decode string input: %v", err)
+	}
+	return req.Model, []string{text}, false
+}
+
+// writeEmbeddings responds with one embedding per text, listed in reverse order, whose
+// single value is the text's length
+func writeEmbeddings(w http.ResponseWriter, texts []string) {
+	var resp OpenAIEmbeddingResponse
+	for i := len(texts) - 1; i >= 0; i-- {
+		resp.Data = append(resp.Data, struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		}{Embedding: []float32{float32(len(texts[i]))}, Index: i})
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeError responds with an OpenAI error payload
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": message}})
+}
+
+func TestOpenAIEmbeddingsFollowIndexOrder(t *testing.T) {
+	var batches atomic.Int32
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			t.Errorf("request sent to %s, want /v1/embeddings", r.URL.Path)
+		}
+		model, texts, batch := embeddingInput(t, r)
+		if model != "test-embed" {
+			t.Errorf("request used model %q, want test-embed", model)
+		}
+		if batch {
+			batches.Add(1)
+		}
+		writeEmbeddings(w, texts)
+	})
+
+	texts := []string{"a", "bb", "ccc", "dddd"}
+	embeddings, err := client.GenerateEmbeddings(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("failed to generate embeddings: %v", err)
+	}
+	if got := batches.Load(); got != 1 {
+		t.Errorf("sent %d array requests, want 1", got)
+	}
+	for i, text := range texts {
+		if got := embeddings[i][0]; got != float32(len(text)) {
+			t.Errorf("embedding %d belongs to a text of length %v, want %d", i, got, len(text))
+		}
+	}
+
+	single, err := client.GenerateEmbedding(context.Background(), "eeeee")
+	if err != nil {
+		t.Fatalf("failed to generate embedding: %v", err)
+	}
+	if single[0] != 5 {
+		t.Errorf("single embedding is %v, want 5", single[0])
+	}
+}
+
+func TestOpenAIChatCompletion(t *testing.T) {
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("request sent to %s, want /v1/chat/completions", r.URL.Path)
+		}
+
+		var req ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.Model != "test-chat" || req.Stream {
+			t.Errorf("request used model %q with stream=%v, want test-chat without streaming", req.Model, req.Stream)
+		}
+		if len(req.Messages) != 1 || req.Messages[0].Role != "user" || !strings.Contains(req.Messages[0].Content, "first memory") {
+			t.Errorf("request sent messages %+v, want one user prompt with the memories", req.Messages)
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{
+				{"message": ChatMessage{Role: "assistant", Content: "a summary"}, "finish_reason": "stop"},
+			},
+		})
+	})
+
+	summary, err := client.ConsolidateMemories(context.Background(), []string{"first memory", "second memory"})
+	if err != nil {
+		t.Fatalf("failed to consolidate: %v", err)
+	}
+	if summary != "a summary" {
+		t.Errorf("summary is %q, want %q", summary, "a summary")
+	}
+}
+
+func TestOpenAIBearerAuth(t *testing.T) {
+	for _, tc := range []struct {
+		key  string
+		want string
+	}{
+		{key: "secret", want: "Bearer secret"},
+		{key: "", want: ""},
+	} {
+		client := newTestOpenAI(t, tc.key, func(w http.ResponseWriter, r *http.Request) {
+			if got := r.Header.Get("Authorization"); got != tc.want {
+				t.Errorf("%s sent Authorization %q, want %q", r.URL.Path, got, tc.want)
+			}
+			if r.URL.Path == "/v1/models" {
+				return
+			}
+			_, texts, _ := embeddingInput(t, r)
+			writeEmbeddings(w, texts)
+		})
+
+		if _, err := client.GenerateEmbedding(context.Background(), "text"); err != nil {
+			t.Errorf("failed to generate embedding: %v", err)
+		}
+		if err := client.HealthCheck(context.Background()); err != nil {
+			t.Errorf("health check failed: %v", err)
+		}
+	}
+}
+
+func TestOpenAIRetriesRateLimitsAndServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	var retryAfterWait time.Duration
+	var lastAttempt time.Time
+
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now()
+		switch attempts.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "slow down")
+		case 2:
+			retryAfterWait = now.Sub(lastAttempt)
+			writeError(w, http.StatusServiceUnavailable, "overloaded")
+		default:
+			_, texts, _ := embeddingInput(t, r)
+			writeEmbeddings(w, texts)
+		}
+		lastAttempt = now
+	})
+
+	if _, err := client.GenerateEmbedding(context.Background(), "text"); err != nil {
+		t.Fatalf("failed to generate embedding: %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("made %d attempts, want 3", got)
+	}
+	if retryAfterWait < time.Second {
+		t.Errorf("retried after %v, want the requested 1s", retryAfterWait)
+	}
+}
+
+func TestOpenAIGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		writeError(w, http.StatusInternalServerError, "broken")
+	})
+
+	_, err := client.GenerateEmbedding(context.Background(), "text")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("error is %v, want an APIError with status 500", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("made %d attempts, want 3", got)
+	}
+}
+
+func TestOpenAIDoesNotRetryClientErrors(t *testing.T) {
+	for _, status := range []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound} {
+		var attempts atomic.Int32
+		client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			writeError(w, status, "rejected")
+		})
+
+		_, err := client.ConsolidateMemories(context.Background(), []string{"memory"})
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != status || apiErr.Message != "rejected" {
+			t.Errorf("error is %v, want an APIError with status %d", err, status)
+		}
+		if got := attempts.Load(); got != 1 {
+			t.Errorf("made %d attempts on status %d, want 1", got, status)
+		}
+	}
+}
+
+func TestOpenAIFallsBackWhenArrayInputIsRejected(t *testing.T) {
+	var batches atomic.Int32
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		_, texts, batch := embeddingInput(t, r)
+		if batch {
+			batches.Add(1)
+			writeError(w, http.StatusBadRequest, "input must be a string")
+			return
+		}
+		writeEmbeddings(w, texts)
+	})
+
+	for range 2 {
+		embeddings, err := client.GenerateEmbeddings(context.Background(), []string{"a", "bb"})
+		if err != nil {
+			t.Fatalf("failed to generate embeddings: %v", err)
+		}
+		if embeddings[0][0] != 1 || embeddings[1][0] != 2 {
+			t.Errorf("embeddings are %v, want them in input order", embeddings)
+		}
+	}
+
+	if !client.noBatch.Load() {
+		t.Error("array input still enabled after the server rejected it")
+	}
+	if got := batches.Load(); got != 1 {
+		t.Errorf("sent %d array requests, want 1 before switching to single requests", got)
+	}
+}
+
+func TestOpenAIKeepsArrayInputAfterBadInput(t *testing.T) {
+	client := newTestOpenAI(t, "secret", func(w http.ResponseWriter, r *http.Request) {
+		_, texts, _ := embeddingInput(t, r)
+		for _, text := range texts {
+			if len(text) > 3 {
+				writeError(w, http.StatusBadRequest, "input is too long")
+				return
+			}
+		}
+		writeEmbeddings(w, texts)
+	})
+
+	if _, err := client.GenerateEmbeddings(context.Background(), []string{"a", "too long"}); err == nil {
+		t.Fatal("embedding an over-length input succeeded")
+	}
+	if client.noBatch.Load() {
+		t.Error("array input disabled because of one bad input")
+	}
+}
@@ -2,10 +2,13 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -41,6 +44,22 @@ func (e EventType) String() string {
 	}
 }
 
+// ParseEventType converts an event name such as "context_init" or "ContextInit" to its EventType
+func ParseEventType(name string) (EventType, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "")) {
+	case "contextinit":
+		return ContextInit, nil
+	case "newcontext":
+		return NewContext, nil
+	case "thresholdreached":
+		return ThresholdReached, nil
+	case "conversationend":
+		return ConversationEnd, nil
+	default:
+		return 0, fmt.Errorf("unknown event type: %s", name)
+	}
+}
+
 // ProcessingEvent represents an event that triggers memory processing
 type ProcessingEvent struct {
 	Type         EventType
@@ -48,6 +67,7 @@ type ProcessingEvent struct {
 	Memories     []*models.MemoryEntry
 	ContextState ContextState
 	Timestamp    time.Time
+	Done         chan error // Receives the handling result when set; must be buffered
 }
 
 // ContextState represents the current state of the context window
@@ -84,9 +104,6 @@ func (cm *ContextMonitor) UpdateUsage(tokens int) {
 
 // EstimateProcessingCost estimates the token cost for memory processing
 func (cm *ContextMonitor) EstimateProcessingCost(memories []*models.MemoryEntry) int {
-	cm.mu.RLock()
-	defer cm.mu.RUnlock()
-
 	// Simple estimation: content length + overhead
 	totalContent := 0
 	for _, mem := range memories {
@@ -103,6 +120,11 @@ func (cm *ContextMonitor) CanSafelyProcess(memories []*models.MemoryEntry) bool
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
+	return cm.canSafelyProcess(memories)
+}
+
+// canSafelyProcess checks the safe limit; callers must hold the lock
+func (cm *ContextMonitor) canSafelyProcess(memories []*models.MemoryEntry) bool {
 	estimatedCost := cm.EstimateProcessingCost(memories)
 	safeLimit := int(float64(cm.MaxTokens) * cm.SafetyMargin)
 
@@ -114,8 +136,9 @@ func (cm *ContextMonitor) GetContextState(memories []*models.MemoryEntry) Contex
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
+	// Nested read locks can deadlock against a waiting UpdateUsage, so use the unlocked helper
 	estimatedCost := cm.EstimateProcessingCost(memories)
-	canProceed := cm.CanSafelyProcess(memories)
+	canProceed := cm.canSafelyProcess(memories)
 
 	return ContextState{
 		WindowSize:    cm.MaxTokens,
@@ -134,6 +157,7 @@ type Processor struct {
 	config     *config.MemoryConfig
 	monitor    *ContextMonitor
 	eventQueue chan ProcessingEvent
+	exclusive  chan struct{} // Held while episodes are selected and consolidated
 	mu         sync.RWMutex
 	running    bool
 	logger     *slog.Logger
@@ -147,6 +171,7 @@ func NewProcessor(journal journal.Journal, llmClient llm.LLM, config *config.Mem
 		config:     config,
 		monitor:    NewContextMonitor(config.MaxTokens, config.SafetyMargin),
 		eventQueue: make(chan ProcessingEvent, 100),
+		exclusive:  make(chan struct{}, 1),
 		logger:     slog.Default(),
 	}
 }
@@ -192,12 +217,17 @@ func (p *Processor) processEvents(ctx context.Context) {
 				return // Channel closed
 			}
 
-			if err := p.handleEvent(ctx, event); err != nil {
+			err := p.handleEvent(ctx, event)
+			if err != nil {
 				p.logger.Error("Failed to handle consolidation event",
 					"event_type", event.Type.String(),
 					"error", err)
 			}
 
+			if event.Done != nil {
+				event.Done <- err
+			}
+
 		case <-ctx.Done():
 			return
 		}
@@ -211,6 +241,27 @@ func (p *Processor) handleEvent(ctx context.Context, event ProcessingEvent) erro
 		"trigger", event.Trigger,
 		"memory_count", len(event.Memories))
 
+	return p.Exclusive(ctx, func(ctx context.Context) error {
+		return p.dispatch(ctx, event)
+	})
+}
+
+// Exclusive runs fn while no event is being handled and blocks events until it returns.
+// Callers that select and consolidate episodes themselves use it so they never pick
+// the same episodes as the processor.
+func (p *Processor) Exclusive(ctx context.Context, fn func(ctx context.Context) error) error {
+	select {
+	case p.exclusive <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-p.exclusive }()
+
+	return fn(ctx)
+}
+
+// dispatch routes an event to its handler
+func (p *Processor) dispatch(ctx context.Context, event ProcessingEvent) error {
 	switch event.Type {
 	case ContextInit:
 		return p.OnContextInit(ctx, event)
@@ -230,7 +281,7 @@ func (p *Processor) OnContextInit(ctx context.Context, event ProcessingEvent) er
 	p.logger.Info("Processing context initialization consolidation")
 
 	// Get recent memories from previous session
-	memories, err := p.journal.GetMemories(ctx, p.config.MemoryCountThreshold)
+	memories, err := p.journal.ListMemories(ctx, p.config.MemoryCountThreshold, journal.UnconsolidatedFilter())
 	if err != nil {
 		return fmt.Errorf("failed to get memories: %w", err)
 	}
@@ -258,7 +309,7 @@ func (p *Processor) OnNewContext(ctx context.Context, event ProcessingEvent) err
 	p.logger.Info("Processing new context consolidation")
 
 	// Check for consolidation opportunities
-	memories, err := p.journal.GetMemories(ctx, p.config.MemoryCountThreshold*2)
+	memories, err := p.journal.ListMemories(ctx, p.config.MemoryCountThreshold*2, journal.UnconsolidatedFilter())
 	if err != nil {
 		return fmt.Errorf("failed to get memories: %w", err)
 	}
@@ -303,7 +354,7 @@ func (p *Processor) OnConversationEnd(ctx context.Context, event ProcessingEvent
 	p.logger.Info("Processing conversation end consolidation")
 
 	// Get all recent memories
-	memories, err := p.journal.GetMemories(ctx, p.config.MemoryCountThreshold*3)
+	memories, err := p.journal.ListMemories(ctx, p.config.MemoryCountThreshold*3, journal.UnconsolidatedFilter())
 	if err != nil {
 		return fmt.Errorf("failed to get memories: %w", err)
 	}
@@ -319,8 +370,11 @@ func (p *Processor) OnConversationEnd(ctx context.Context, event ProcessingEvent
 	return p.processMemories(ctx, selectedMemories, "conversation_end")
 }
 
-// selectMemoriesForConsolidation selects memories for consolidation based on importance
+// selectMemoriesForConsolidation selects memories for consolidation based on importance,
+// skipping episodes that have already been consolidated
 func (p *Processor) selectMemoriesForConsolidation(memories []*models.MemoryEntry) []*models.MemoryEntry {
+	memories = slices.DeleteFunc(slices.Clone(memories), journal.IsConsolidated)
+
 	// Score all memories
 	scores := make([]struct {
 		memory *models.MemoryEntry
@@ -411,9 +465,18 @@ func (p *Processor) processMemories(ctx context.Context, memories []*models.Memo
 		"trigger", trigger)
 
 	// Perform consolidation using the memory store
-	if err := p.journal.ConsolidateMemories(ctx, memories); err != nil {
+	semantic, err := p.journal.ConsolidateMemories(ctx, memories, journal.ConsolidateOptions{})
+	if errors.Is(err, journal.ErrAlreadyConsolidated) {
+		p.logger.Info("Selected memories were consolidated elsewhere", "trigger", trigger)
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("failed to consolidate memories: %w", err)
 	}
+	// The sources stay unconsolidated, so report the run as failed rather than retrying at once
+	if journal.IsExtractive(semantic) {
+		return fmt.Errorf("model unavailable, stored extractive summary %s", semantic.ID)
+	}
 
 	// Update access tracking for consolidated memories
 	for _, mem := range memories {
@@ -455,7 +518,7 @@ func (p *Processor) scheduleEarlyConsolidation(ctx context.Context) error {
 	p.logger.Info("Scheduling early consolidation due to context window constraints")
 
 	// Get a smaller set of memories for emergency consolidation
-	memories, err := p.journal.GetMemories(ctx, p.config.MemoryCountThreshold/2)
+	memories, err := p.journal.ListMemories(ctx, p.config.MemoryCountThreshold/2, journal.UnconsolidatedFilter())
 	if err != nil {
 		return fmt.Errorf("failed to get memories for early consolidation: %w", err)
 	}
@@ -476,20 +539,46 @@ func (p *Processor) scheduleEarlyConsolidation(ctx context.Context) error {
 
 // TriggerEvent triggers a consolidation event
 func (p *Processor) TriggerEvent(eventType EventType, trigger string, memories []*models.MemoryEntry) error {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+	return p.enqueue(p.newEvent(eventType, trigger, memories))
+}
 
-	if !p.running {
-		return fmt.Errorf("memory processor not running")
+// TriggerEventAndWait triggers a consolidation event and blocks until the processor
+// has handled it, returning the handling error, or until ctx is done
+func (p *Processor) TriggerEventAndWait(ctx context.Context, eventType EventType, trigger string, memories []*models.MemoryEntry) error {
+	event := p.newEvent(eventType, trigger, memories)
+	event.Done = make(chan error, 1)
+
+	if err := p.enqueue(event); err != nil {
+		return err
 	}
 
-	event := ProcessingEvent{
+	select {
+	case err := <-event.Done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("stopped waiting for %s event: %w", eventType, ctx.Err())
+	}
+}
+
+// newEvent builds a processing event with the current context state
+func (p *Processor) newEvent(eventType EventType, trigger string, memories []*models.MemoryEntry) ProcessingEvent {
+	return ProcessingEvent{
 		Type:         eventType,
 		Trigger:      trigger,
 		Memories:     memories,
 		ContextState: p.monitor.GetContextState(memories),
 		Timestamp:    time.Now(),
 	}
+}
+
+// enqueue adds an event to the queue without blocking
+func (p *Processor) enqueue(event ProcessingEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.running {
+		return fmt.Errorf("memory processor not running")
+	}
 
 	select {
 	case p.eventQueue <- event:
@@ -504,16 +593,23 @@ func (p *Processor) UpdateContextUsage(tokens int) {
 	p.monitor.UpdateUsage(tokens)
 }
 
+// ContextState returns the monitored context window state without pending memories
+func (p *Processor) ContextState() ContextState {
+	return p.monitor.GetContextState(nil)
+}
+
 // GetStats returns memory processor statistics
 func (p *Processor) GetStats() map[string]any {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
+	state := p.monitor.GetContextState(nil)
+
 	return map[string]any{
 		"running":           p.running,
 		"queue_length":      len(p.eventQueue),
-		"current_tokens":    p.monitor.CurrentTokens,
-		"max_tokens":        p.monitor.MaxTokens,
+		"current_tokens":    state.CurrentUsage,
+		"max_tokens":        state.WindowSize,
 		"safety_margin":     p.monitor.SafetyMargin,
 		"memory_threshold":  p.config.MemoryCountThreshold,
 		"context_threshold": p.config.ContextUsageThreshold,
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MemoryFilter restricts memory queries by payload fields. A memory matches when
+// every Must condition matches, at least one Should condition matches (if any are
+// given) and no MustNot condition matches.
+//
+// Fields are metadata keys such as "source", "captured_at" or "tags", or the
+// top-level fields "type", "strength" and "created_at". Timestamps are Unix seconds.
+type MemoryFilter struct {
+	Must    []FilterCondition `json:"must,omitempty"`
+	Should  []FilterCondition `json:"should,omitempty"`
+	MustNot []FilterCondition `json:"must_not,omitempty"`
+}
+
+// FilterCondition matches a single field, or nests another filter for grouping.
+// Exactly one of Equals, In, Range or Filter must be set. On list-valued fields
+// such as tags, Equals and In match when any element matches.
+type FilterCondition struct {
+	Field  string        `json:"field,omitempty"`
+	Equals any           `json:"equals,omitempty"` // string, number or bool
+	In     []any         `json:"in,omitempty"`     // strings or integers
+	Range  *RangeFilter  `json:"range,omitempty"`  // numeric or Unix-second timestamp bounds
+	Filter *MemoryFilter `json:"filter,omitempty"` // nested filter (Field is ignored)
+}
+
+// RangeFilter bounds a numeric field; unset bounds are open
+type RangeFilter struct {
+	GT  *float64 `json:"gt,omitempty"`
+	GTE *float64 `json:"gte,omitempty"`
+	LT  *float64 `json:"lt,omitempty"`
+	LTE *float64 `json:"lte,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no conditions and therefore matches everything
+func (f *MemoryFilter) IsEmpty() bool {
+	return f == nil || (len(f.Must) == 0 && len(f.Should) == 0 && len(f.MustNot) == 0)
+}
+
+// Validate checks that every condition is well formed
+func (f *MemoryFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	for _, group := range [][]FilterCondition{f.Must, f.Should, f.MustNot} {
+		for _, condition := range group {
+			if err := condition.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that the condition sets exactly one matcher and names a field when needed
+func (c *FilterCondition) Validate() error {
+	matchers := 0
+	if c.Equals != nil {
+		matchers++
+	}
+	if c.In != nil {
+		matchers++
+	}
+	if c.Range != nil {
+		matchers++
+	}
+	if c.Filter != nil {
+		matchers++
+	}
+	if matchers != 1 {
+		return fmt.Errorf("filter condition on %q must set exactly one of equals, in, range or filter", c.Field)
+	}
+
+	if c.Filter != nil {
+		return c.Filter.Validate()
+	}
+
+	if c.Field == "" {
+		return fmt.Errorf("filter condition field is required")
+	}
+
+	// An empty list has no agreed meaning across backends, so it is rejected
+	if c.In != nil && len(c.In) == 0 {
+		return fmt.Errorf("in filter on %q must list at least one value", c.Field)
+	}
+
+	if c.Range != nil && c.Range.GT == nil && c.Range.GTE == nil && c.Range.LT == nil && c.Range.LTE == nil {
+		return fmt.Errorf("range filter on %q must set at least one bound", c.Field)
+	}
+
+	return nil
+}
+
+// BuildFilter converts the list query parameters into a memory filter
+func (r *GetMemoriesRequest) BuildFilter(now time.Time) (*MemoryFilter, error) {
+	filter := &MemoryFilter{}
+
+	if r.Filter != "" {
+		if err := json.Unmarshal([]byte(r.Filter), filter); err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+
+	if r.Source != "" {
+		filter.Must = append(filter.Must, FilterCondition{Field: "source", Equals: r.Source})
+	}
+
+	if len(r.Tags) > 0 {
+		tags := make([]any, len(r.Tags))
+		for i, tag := range r.Tags {
+			tags[i] = tag
+		}
+		filter.Must = append(filter.Must, FilterCondition{Field: "tags", In: tags})
+	}
+
+	if r.Since != "" || r.Until != "" {
+		bounds := &RangeFilter{}
+		if r.Since != "" {
+			since, err := ParseTimeBound(r.Since, now)
+			if err != nil {
+				return nil, fmt.Errorf("invalid since: %w", err)
+			}
+			value := float64(since.Unix())
+			bounds.GTE = &value
+		}
+		if r.Until != "" {
+			until, err := ParseTimeBound(r.Until, now)
+			if err != nil {
+				return nil, fmt.Errorf("invalid until: %w", err)
+			}
+			value := float64(until.Unix())
+			bounds.LTE = &value
+		}
+		filter.Must = append(filter.Must, FilterCondition{Field: "created_at", Range: bounds})
+	}
+
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+	return filter, nil
+}
+
+// ParseTimeBound parses an RFC3339 timestamp, or an age such as "90m", "48h" or "2d"
+// which is subtracted from now
+func ParseTimeBound(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("expected RFC3339 time or age, got %q", value)
+		}
+		return now.AddDate(0, 0, -n), nil
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return time.Time{}, fmt.Errorf("expected RFC3339 time or age, got %q", value)
+	}
+	return now.Add(-age), nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
@@ -37,8 +38,45 @@ const (
 	
 	// AssociationContextual represents memories from similar contexts
 	AssociationContextual AssociationType = "contextual"
+	
+	// AssociationDerivedFrom links a consolidated memory (source) to a memory it was created from (target)
+	AssociationDerivedFrom AssociationType = "derived_from"
 )
 
+// TypeAll selects every configured memory type in search requests.
+// It is a query selector only and is never assigned to a stored memory.
+const TypeAll MemoryType = "all"
+
+// TypeChunk marks a section of a long episodic memory stored as its own vector.
+// Chunks are searched on behalf of their parent memory and never returned directly.
+const TypeChunk MemoryType = "chunk"
+
+// SearchMode represents how memories are matched against a search query
+type SearchMode string
+
+const (
+	// SearchModeVector matches memories by embedding similarity
+	SearchModeVector SearchMode = "vector"
+	
+	// SearchModeKeyword matches memories by BM25-ranked keyword overlap
+	SearchModeKeyword SearchMode = "keyword"
+	
+	// SearchModeHybrid fuses vector and keyword rankings with reciprocal rank fusion
+	SearchModeHybrid SearchMode = "hybrid"
+)
+
+// ParseSearchMode validates a search mode string, defaulting to vector when empty
+func ParseSearchMode(mode string) (SearchMode, error) {
+	switch SearchMode(mode) {
+	case "":
+		return SearchModeVector, nil
+	case SearchModeVector, SearchModeKeyword, SearchModeHybrid:
+		return SearchMode(mode), nil
+	default:
+		return "", fmt.Errorf("unsupported search mode: %s", mode)
+	}
+}
+
 // MemoryAssociation represents a relationship between two memories
 type MemoryAssociation struct {
 	ID         string          `json:"id"`           // Unique association ID
@@ -73,6 +111,172 @@ type MemoryEntry struct {
 	Strength      float32           `json:"strength"`
 	Score         MemoryScore       `json:"score"`               // Enhanced scoring
 	AssociationIDs []string         `json:"association_ids"`     // Related memory references
+	Similarity    float64           `json:"similarity,omitempty"` // Raw vector similarity set by search results (not persisted)
+	RankScore     float64           `json:"rank_score,omitempty"` // Final search rank combining relevance and importance (not persisted)
+	Explanation   *RankExplanation  `json:"explanation,omitempty"` // Rank breakdown when a search asks for it (not persisted)
+	MatchedChunk  string            `json:"matched_chunk,omitempty"` // Best matching section of a chunked memory, set by search results (not persisted)
+}
+
+// RankExplanation breaks a search rank down into its components.
+// FinalScore = Relevance * (1 + CompositeScore) * (1 + AssociationBoost), where
+// CompositeScore = BaseImportance * (AccessFrequency + StrengthComponent) * Decay.
+type RankExplanation struct {
+	Similarity        float64 `json:"similarity"`         // Raw vector similarity (0 when only matched by keyword)
+	Relevance         float64 `json:"relevance"`          // Retrieval relevance used for ranking (weighted similarity or normalized fused rank)
+	BaseImportance    float64 `json:"base_importance"`    // Strength and type based importance
+	Decay             float64 `json:"decay"`              // Time-based decay factor
+	AccessFrequency   float64 `json:"access_frequency"`   // Weighted access-frequency component
+	StrengthComponent float64 `json:"strength_component"` // Weighted strength component
+	CompositeScore    float64 `json:"composite_score"`    // MemoryScore.CompositeScore
+	AssociationBoost  float64 `json:"association_boost"`  // Boost from the memory's association strengths
+	FinalScore        float64 `json:"final_score"`        // Final rank score
+}
+
+// MemoryVersion is a revision of a memory's content and metadata kept when the memory is edited
+type MemoryVersion struct {
+	ID       string         `json:"id"`                 // Unique version ID
+	MemoryID string         `json:"memory_id"`          // Memory this revision belongs to
+	Version  int            `json:"version"`            // Revision number, starting at 1 for the captured content
+	Content  string         `json:"content"`            // Content of this revision
+	Metadata map[string]any `json:"metadata,omitempty"` // Metadata of this revision
+	EditedAt time.Time      `json:"edited_at"`          // When this revision was written
+	EditedBy string         `json:"edited_by"`          // Source that wrote this revision
+}
+
+// MemoryUpdate describes an edit to a memory. Metadata keys are merged into the
+// existing metadata; a nil value removes the key.
+type MemoryUpdate struct {
+	Content  *string        // Replacement content, re-embedded when it changes
+	Metadata map[string]any // Metadata changes to merge
+	Editor   string         // Source making the edit
+}
+
+// ForgetResult reports the outcome of deleting memories and cascading to their associations
+type ForgetResult struct {
+	Deleted             []string `json:"deleted"`              // IDs of the memories removed
+	NotFound            []string `json:"not_found,omitempty"`  // Requested IDs that did not exist
+	AssociationsRemoved int      `json:"associations_removed"` // Associations that referenced the deleted memories
+	NeighborsUpdated    int      `json:"neighbors_updated"`    // Neighbors whose AssociationIDs were pruned
+}
+
+// LineageNode is a memory in a consolidation lineage together with the memories it was derived from
+type LineageNode struct {
+	ID      string         `json:"id"`                // Memory ID
+	Memory  *MemoryEntry   `json:"memory,omitempty"`  // The memory, absent when it has been deleted
+	Missing bool           `json:"missing,omitempty"` // The source was deleted after consolidation
+	Sources []*LineageNode `json:"sources,omitempty"` // Memories this one was consolidated from
+}
+
+// RollbackResult reports the outcome of undoing a consolidation
+type RollbackResult struct {
+	ConsolidatedID    string        `json:"consolidated_id"`              // The deleted consolidated memory
+	Restored          []string      `json:"restored"`                     // Sources now eligible for consolidation again
+	StillConsolidated []string      `json:"still_consolidated,omitempty"` // Sources also consolidated into other memories
+	Missing           []string      `json:"missing,omitempty"`            // Sources deleted since the consolidation
+	Unaffected        []string      `json:"unaffected,omitempty"`         // Sources not consolidated into the memory, such as knowledge record evidence
+	Forget            *ForgetResult `json:"forget"`                       // Outcome of deleting the consolidated memory
+}
+
+// RetentionReason explains which retention policy selected a memory for removal
+type RetentionReason string
+
+const (
+	// RetentionExpired marks episodic memories older than the retention period
+	RetentionExpired RetentionReason = "expired"
+	
+	// RetentionWeak marks memories whose decayed strength fell below the strength threshold
+	RetentionWeak RetentionReason = "weak"
+	
+	// RetentionOverCapacity marks the lowest scored memories beyond the size limit
+	RetentionOverCapacity RetentionReason = "over_capacity"
+)
+
+// RetentionCandidate describes a memory selected for removal by a retention run
+type RetentionCandidate struct {
+	ID              string          `json:"id"`
+	Type            MemoryType      `json:"type"`
+	Reason          RetentionReason `json:"reason"`
+	CreatedAt       time.Time       `json:"created_at"`
+	DecayedStrength float64         `json:"decayed_strength"`
+	CompositeScore  float64         `json:"composite_score"`
+}
+
+// RetentionReport summarizes a retention run. Result is nil for dry runs.
+type RetentionReport struct {
+	DryRun      bool                  `json:"dry_run"`
+	Scanned     int                   `json:"scanned"`     // Memories examined across all types
+	Candidates  []*RetentionCandidate `json:"candidates"`  // Memories selected for removal
+	Result      *ForgetResult         `json:"result,omitempty"`
+	StartedAt   time.Time             `json:"started_at"`
+	CompletedAt time.Time             `json:"completed_at"`
+}
+
+// JobStatus represents the lifecycle state of a background job
+type JobStatus string
+
+const (
+	// JobPending marks a job that has been accepted but not started
+	JobPending JobStatus = "pending"
+	
+	// JobRunning marks a job that is currently executing
+	JobRunning JobStatus = "running"
+	
+	// JobCompleted marks a job that finished; individual groups may still have failed
+	JobCompleted JobStatus = "completed"
+	
+	// JobFailed marks a job that stopped on an error
+	JobFailed JobStatus = "failed"
+	
+	// JobCancelled marks a job stopped by a cancel request
+	JobCancelled JobStatus = "cancelled"
+	
+	// JobInterrupted marks a job that was still running when the service restarted
+	JobInterrupted JobStatus = "interrupted"
+)
+
+// Finished reports whether the status is terminal
+func (s JobStatus) Finished() bool {
+	switch s {
+	case JobCompleted, JobFailed, JobCancelled, JobInterrupted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Job is the persisted record of a background job such as a consolidation run
+type Job struct {
+	ID              string      `json:"id"`
+	Type            string      `json:"type"`
+	Status          JobStatus   `json:"status"`
+	Message         string      `json:"message,omitempty"`
+	CancelRequested bool        `json:"cancel_requested,omitempty"` // Cancellation requested; the job is still stopping
+	Error           string      `json:"error,omitempty"`
+	Progress        JobProgress `json:"progress"`
+	Groups          []*JobGroup `json:"groups,omitempty"`
+	SemanticIDs     []string    `json:"semantic_ids"` // Semantic memories created so far
+	CreatedAt       time.Time   `json:"created_at"`
+	StartedAt       *time.Time  `json:"started_at,omitempty"`
+	CompletedAt     *time.Time  `json:"completed_at,omitempty"`
+}
+
+// JobProgress summarizes how far a consolidation job has come
+type JobProgress struct {
+	TotalMemories      int `json:"total_memories"`      // Episodic memories considered
+	GroupsFormed       int `json:"groups_formed"`       // Association groups found
+	GroupsCompleted    int `json:"groups_completed"`    // Groups consolidated, skipped or failed
+	GroupsConsolidated int `json:"groups_consolidated"` // Groups turned into semantic memories
+	GroupsFailed       int `json:"groups_failed"`       // Groups whose consolidation errored
+	MemoriesProcessed  int `json:"memories_processed"`  // Memories in consolidated groups
+}
+
+// JobGroup tracks the consolidation of one group of associated memories
+type JobGroup struct {
+	MemoryIDs  []string  `json:"memory_ids"`
+	Status     JobStatus `json:"status"`
+	Skipped    bool      `json:"skipped,omitempty"`     // Single-memory groups are not consolidated
+	SemanticID string    `json:"semantic_id,omitempty"` // Semantic memory created from the group
+	Error      string    `json:"error,omitempty"`
 }
 
 // Memory represents the base interface for all memory types
@@ -104,12 +308,12 @@ type SemanticMemory struct {
 
 // Concept represents a semantic knowledge unit
 type Concept struct {
-	ID           string
-	Name         string
-	Definition   string
-	Relationships map[string]float32 // Related concepts and their strength
-	Examples     []string
-	CreatedFrom  []string // IDs of episodic memories this was derived from
+	ID            string             `json:"id,omitempty"`
+	Name          string             `json:"name"`
+	Definition    string             `json:"definition"`
+	Relationships map[string]float32 `json:"relationships,omitempty"` // Related concepts and their strength
+	Examples      []string           `json:"examples,omitempty"`
+	CreatedFrom   []string           `json:"created_from"` // IDs of episodic memories this was derived from
 }
 
 // ProceduralMemory represents learned patterns and behaviors
@@ -120,12 +324,13 @@ type ProceduralMemory struct {
 
 // Pattern represents a learned behavioral pattern
 type Pattern struct {
-	ID          string
-	Trigger     string   // What activates this pattern
-	Actions     []string // Sequence of actions
-	Frequency   int      // How often this pattern has been used
-	Success     float32  // Success rate of this pattern
-	LastUsed    time.Time
+	ID        string    `json:"id,omitempty"`
+	Trigger   string    `json:"trigger"`             // What activates this pattern
+	Actions   []string  `json:"actions"`             // Sequence of actions
+	Frequency int       `json:"frequency,omitempty"` // How often this pattern has been used
+	Success   float32   `json:"success,omitempty"`   // Success rate of this pattern
+	LastUsed  time.Time `json:"last_used,omitzero"`
+	Evidence  []string  `json:"evidence"` // Memory IDs the pattern was observed in
 }
 
 // MetacognitiveMemory represents self-reflection and learning strategies
@@ -136,12 +341,21 @@ type MetacognitiveMemory struct {
 
 // Insight represents a metacognitive observation
 type Insight struct {
-	ID          string
-	Type        string // "learning_strategy", "mistake_pattern", "improvement"
-	Description string
-	Evidence    []string // Memory IDs that support this insight
-	Confidence  float32
-	CreatedAt   time.Time
+	ID          string    `json:"id,omitempty"`
+	Type        string    `json:"type"` // "learning_strategy", "mistake_pattern", "improvement"
+	Description string    `json:"description"`
+	Evidence    []string  `json:"evidence"` // Memory IDs that support this insight
+	Confidence  float32   `json:"confidence"`
+	CreatedAt   time.Time `json:"created_at,omitzero"`
+}
+
+// Knowledge is the structured result of consolidating a group of memories: a summary
+// plus the concepts, patterns and insights the memories support
+type Knowledge struct {
+	Summary  string     `json:"summary"`
+	Concepts []*Concept `json:"concepts"`
+	Patterns []*Pattern `json:"patterns"`
+	Insights []*Insight `json:"insights"`
 }
 
 // HTTP API request/response types
@@ -156,19 +370,59 @@ type CaptureMemoryResponse struct {
 	Message string `json:"message"`
 }
 
+type CaptureBatchRequest struct {
+	Memories []*CaptureMemoryRequest `json:"memories" binding:"required,min=1,max=1000,dive,required"`
+}
+
+type CaptureBatchResponse struct {
+	IDs     []string `json:"ids"` // Captured memory IDs in request order
+	Count   int      `json:"count"`
+	Message string   `json:"message"`
+}
+
+// CaptureBatchErrorResponse reports a batch that failed partway; the memories before
+// the failure stay captured, so their IDs are returned for the client to reconcile
+type CaptureBatchErrorResponse struct {
+	Error   string   `json:"error"`
+	Message string   `json:"message"`
+	IDs     []string `json:"ids"`   // Memories captured before the failure, in request order
+	Count   int      `json:"count"` // Number of memories captured before the failure
+}
+
 type GetMemoriesRequest struct {
-	Limit uint32 `json:"limit,omitempty"`
+	Limit  uint32   `json:"limit,omitempty" form:"limit"`
+	Source string   `json:"source,omitempty" form:"source"` // Only memories captured from this source
+	Tags   []string `json:"tags,omitempty" form:"tag"`      // Only memories carrying any of these tags
+	Since  string   `json:"since,omitempty" form:"since"`   // RFC3339 time or age such as "48h" or "2d"
+	Until  string   `json:"until,omitempty" form:"until"`   // RFC3339 time or age such as "48h" or "2d"
+	Filter string   `json:"filter,omitempty" form:"filter"` // JSON-encoded MemoryFilter combined with the fields above
+}
+
+type GetMemoryRequest struct {
+	IncludeAssociations bool `json:"include_associations,omitempty" form:"include_associations"` // Attach the memory's associations
+	IncludeSources      bool `json:"include_sources,omitempty" form:"include_sources"`           // Attach the memories it was consolidated from
+}
+
+type GetMemoryResponse struct {
+	*MemoryEntry
+	Associations []*MemoryAssociation `json:"associations,omitempty"`
+	Sources      []*MemoryEntry       `json:"sources,omitempty"`
 }
 
 type SearchMemoriesRequest struct {
-	Content    string `json:"content"`
-	MemoryType string `json:"memory_type,omitempty"`
-	Limit      uint64 `json:"limit,omitempty"`
+	Content     string             `json:"content"`
+	MemoryType  string             `json:"memory_type,omitempty"`  // A memory type, or "all" to search every type
+	Mode        string             `json:"mode,omitempty"`         // "vector" (default), "keyword" or "hybrid"
+	Limit       uint64             `json:"limit,omitempty"`
+	TypeWeights map[string]float64 `json:"type_weights,omitempty"` // Per-type weights when memory_type is "all"
+	Explain     bool               `json:"explain,omitempty"`      // Include a rank breakdown on each hit
+	Filter      *MemoryFilter      `json:"filter,omitempty"`       // Restrict results by metadata and time range
 }
 
 type SearchMemoriesResponse struct {
 	Memories []*MemoryEntry `json:"memories"`
 	Query    string         `json:"query"`
+	Mode     SearchMode     `json:"mode"`
 	Count    int            `json:"count"`
 	Limit    uint64         `json:"limit"`
 }
@@ -179,12 +433,84 @@ type GetMemoriesResponse struct {
 	Limit    uint32         `json:"limit"`
 }
 
+type ConsolidateRequest struct {
+	BatchSize int `json:"batch_size,omitempty" binding:"min=0"` // Maximum memories per consolidation prompt (0 uses the token budget only)
+}
+
 type ConsolidateResponse struct {
-	Message            string `json:"message"`
-	GroupsFormed       int    `json:"groups_formed"`
-	GroupsConsolidated int    `json:"groups_consolidated"`
-	MemoriesProcessed  int    `json:"memories_processed"`
-	TotalMemories      int    `json:"total_memories"`
+	JobID   string    `json:"job_id"`
+	Status  JobStatus `json:"status"`
+	Message string    `json:"message"`
+}
+
+type ListJobsResponse struct {
+	Jobs  []*Job `json:"jobs"`
+	Count int    `json:"count"`
+}
+
+type UpdateMemoryRequest struct {
+	Content  *string        `json:"content,omitempty"`  // Replacement content
+	Metadata map[string]any `json:"metadata,omitempty"` // Metadata changes to merge (null removes a key)
+	Editor   string         `json:"editor,omitempty"`   // Source making the edit
+}
+
+type UpdateMemoryResponse struct {
+	Memory  *MemoryEntry `json:"memory"`
+	Version int          `json:"version"`
+	Message string       `json:"message"`
+}
+
+type RestoreVersionRequest struct {
+	Editor string `json:"editor,omitempty"` // Source performing the restore
+}
+
+type ListVersionsResponse struct {
+	MemoryID string           `json:"memory_id"`
+	Versions []*MemoryVersion `json:"versions"`
+	Count    int              `json:"count"`
+}
+
+type DeleteMemoryResponse struct {
+	ID                  string `json:"id"`
+	Message             string `json:"message"`
+	AssociationsRemoved int    `json:"associations_removed"`
+	NeighborsUpdated    int    `json:"neighbors_updated"`
+}
+
+type ProcessorEventRequest struct {
+	Type    string `json:"type"`              // "context_init", "new_context", "threshold_reached" or "conversation_end"
+	Trigger string `json:"trigger,omitempty"` // Why the event was raised, recorded in processor logs
+}
+
+type ProcessorEventResponse struct {
+	Type    string `json:"type"`
+	Trigger string `json:"trigger"`
+	Message string `json:"message"`
+}
+
+type ContextUsageRequest struct {
+	Tokens int `json:"tokens" binding:"min=0"` // Tokens currently used in the agent's context window
+}
+
+type ContextUsageResponse struct {
+	CurrentTokens     int     `json:"current_tokens"`
+	MaxTokens         int     `json:"max_tokens"`
+	Usage             float64 `json:"usage"`              // CurrentTokens as a fraction of MaxTokens
+	ThresholdExceeded bool    `json:"threshold_exceeded"` // Usage has reached memory.context_usage_threshold
+	CanConsolidate    bool    `json:"can_consolidate"`    // Consolidation fits within the safety margin
+}
+
+// CacheStats reports how well the embedding cache is working
+type CacheStats struct {
+	Enabled    bool    `json:"enabled"`
+	Persistent bool    `json:"persistent"` // Entries are also stored on disk
+	Entries    int     `json:"entries"`    // Entries held in memory
+	MaxEntries int     `json:"max_entries"`
+	Hits       uint64  `json:"hits"`
+	Misses     uint64  `json:"misses"`
+	Evictions  uint64  `json:"evictions"` // Entries dropped to stay within max_entries
+	Expired    uint64  `json:"expired"`   // Entries dropped because they outlived the TTL
+	HitRate    float64 `json:"hit_rate"`  // Hits as a fraction of lookups
 }
 
 type StatsResponse struct {
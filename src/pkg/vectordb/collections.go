@@ -32,14 +32,15 @@ type MemoryCollection interface {
 	// Store saves a memory entry to the appropriate collection based on its type
 	Store(ctx context.Context, entry *models.MemoryEntry) error
 	
-	// Query performs vector similarity search for a specific memory type
-	Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64) ([]*models.MemoryEntry, error)
+	// Query performs vector similarity search for a specific memory type, restricted by an optional filter.
+	// Each result carries its raw score in MemoryEntry.Similarity.
+	Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error)
 	
 	// Retrieve gets a specific memory entry by ID and type
 	Retrieve(ctx context.Context, memType models.MemoryType, id string) (*models.MemoryEntry, error)
 	
-	// GetRecent retrieves recent memories by creation time without similarity search
-	GetRecent(ctx context.Context, memType models.MemoryType, limit uint32) ([]*models.MemoryEntry, error)
+	// GetRecent retrieves recent memories by creation time without similarity search, restricted by an optional filter
+	GetRecent(ctx context.Context, memType models.MemoryType, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error)
 	
 	// Count returns the number of memories of a specific type
 	Count(ctx context.Context, memType models.MemoryType) (uint64, error)
@@ -47,6 +48,9 @@ type MemoryCollection interface {
 	// Delete removes memories by their IDs from a specific type collection
 	Delete(ctx context.Context, memType models.MemoryType, ids []string) error
 	
+	// KeywordQuery performs a BM25-ranked full-text search for a specific memory type, restricted by an optional filter
+	KeywordQuery(ctx context.Context, memType models.MemoryType, query string, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error)
+	
 	// GetAll retrieves all memories of a type with cursor-based pagination
 	GetAll(ctx context.Context, memType models.MemoryType, cursor string, limit uint32) (entries []*models.MemoryEntry, nextCursor string, err error)
 }
@@ -76,4 +80,16 @@ type AssociationCollection interface {
 	
 	// GetAll retrieves all associations with pagination
 	GetAll(ctx context.Context, cursor string, limit uint32) (associations []*models.MemoryAssociation, nextCursor string, err error)
+}
+
+// VersionCollection handles prior revisions of edited memories
+type VersionCollection interface {
+	// Store saves a memory version
+	Store(ctx context.Context, version *models.MemoryVersion) error
+	
+	// GetByMemoryID retrieves every version of a memory ordered by version number
+	GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryVersion, error)
+	
+	// DeleteByMemoryID removes every version of a memory
+	DeleteByMemoryID(ctx context.Context, memoryID string) error
 }
\ No newline at end of file
@@ -0,0 +1,60 @@
+package vectordb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pageCursor encodes the position of the last item of a page in creation order. Paging
+// resumes strictly after that position, so it continues even if the item itself is
+// deleted before the next page is requested.
+type pageCursor struct {
+	createdAt time.Time
+	id        string
+}
+
+// String encodes the cursor as Unix nanoseconds and ID, with 0 for the zero time
+func (pc pageCursor) String() string {
+	var nanos int64
+	if !pc.createdAt.IsZero() {
+		nanos = pc.createdAt.UnixNano()
+	}
+	return fmt.Sprintf("%d/%s", nanos, pc.id)
+}
+
+// parsePageCursor decodes a cursor produced by pageCursor.String
+func parsePageCursor(cursor string) (pageCursor, error) {
+	nanos, id, found := strings.Cut(cursor, "/")
+	if !found || id == "" {
+		return pageCursor{}, fmt.Errorf("invalid page cursor %q", cursor)
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return pageCursor{}, fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+	}
+
+	position := pageCursor{id: id}
+	if unixNano != 0 {
+		position.createdAt = time.Unix(0, unixNano)
+	}
+	return position, nil
+}
+
+// afterNewestFirst reports whether an item sorts after the cursor in newest-first order
+func (pc pageCursor) afterNewestFirst(createdAt time.Time, id string) bool {
+	if createdAt.Equal(pc.createdAt) {
+		return id > pc.id
+	}
+	return createdAt.Before(pc.createdAt)
+}
+
+// afterOldestFirst reports whether an item sorts after the cursor in oldest-first order
+func (pc pageCursor) afterOldestFirst(createdAt time.Time, id string) bool {
+	if createdAt.Equal(pc.createdAt) {
+		return id > pc.id
+	}
+	return createdAt.After(pc.createdAt)
+}
@@ -0,0 +1,147 @@
+package vectordb
+
+import (
+	"fmt"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// MatchesFilter evaluates a memory filter in process with the same semantics
+// as the Qdrant translation, for backends that filter after loading rows and for
+// callers that check memories they loaded by ID
+func MatchesFilter(entry *models.MemoryEntry, filter *models.MemoryFilter) bool {
+	if filter.IsEmpty() {
+		return true
+	}
+
+	for _, condition := range filter.Must {
+		if !matchesCondition(entry, condition) {
+			return false
+		}
+	}
+
+	if len(filter.Should) > 0 {
+		matched := false
+		for _, condition := range filter.Should {
+			if matchesCondition(entry, condition) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	for _, condition := range filter.MustNot {
+		if matchesCondition(entry, condition) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesCondition evaluates a single filter condition against a memory
+func matchesCondition(entry *models.MemoryEntry, condition models.FilterCondition) bool {
+	if condition.Filter != nil {
+		return MatchesFilter(entry, condition.Filter)
+	}
+
+	value, exists := memoryFieldValue(entry, condition.Field)
+	if !exists {
+		return false
+	}
+
+	// List-valued fields match when any element matches, as in Qdrant
+	values := []any{value}
+	if list, ok := value.([]any); ok {
+		values = list
+	} else if list, ok := value.([]string); ok {
+		values = make([]any, len(list))
+		for i, v := range list {
+			values[i] = v
+		}
+	}
+
+	for _, v := range values {
+		switch {
+		case condition.Equals != nil:
+			if filterValuesEqual(v, condition.Equals) {
+				return true
+			}
+		case condition.In != nil:
+			for _, candidate := range condition.In {
+				if filterValuesEqual(v, candidate) {
+					return true
+				}
+			}
+		case condition.Range != nil:
+			if number, ok := filterNumber(v); ok && inRange(number, condition.Range) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// memoryFieldValue resolves a filter field to the value stored for a memory.
+// Top-level fields use the same representation as the Qdrant payload.
+func memoryFieldValue(entry *models.MemoryEntry, field string) (any, bool) {
+	switch field {
+	case "type":
+		return string(entry.Type), true
+	case "strength":
+		return float64(entry.Strength), true
+	case "created_at":
+		return entry.CreatedAt.Unix(), true
+	}
+
+	value, exists := entry.Metadata[field]
+	return value, exists
+}
+
+// filterValuesEqual compares a stored value with a filter value, treating all numeric types alike
+func filterValuesEqual(stored, expected any) bool {
+	if a, ok := filterNumber(stored); ok {
+		b, ok := filterNumber(expected)
+		return ok && a == b
+	}
+	return fmt.Sprint(stored) == fmt.Sprint(expected)
+}
+
+// filterNumber converts numeric values to float64
+func filterNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// inRange reports whether a number satisfies every set bound of a range
+func inRange(number float64, r *models.RangeFilter) bool {
+	if r.GT != nil && !(number > *r.GT) {
+		return false
+	}
+	if r.GTE != nil && !(number >= *r.GTE) {
+		return false
+	}
+	if r.LT != nil && !(number < *r.LT) {
+		return false
+	}
+	if r.LTE != nil && !(number <= *r.LTE) {
+		return false
+	}
+	return true
+}
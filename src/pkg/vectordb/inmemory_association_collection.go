@@ -0,0 +1,227 @@
+package vectordb
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// inMemoryAssociationCollection implements AssociationCollection with process-local maps
+type inMemoryAssociationCollection struct {
+	mu           sync.RWMutex
+	associations map[string]*models.MemoryAssociation
+	// Indexes of association IDs keyed by source and target memory ID
+	sourceIndex map[string]map[string]struct{}
+	targetIndex map[string]map[string]struct{}
+}
+
+// newInMemoryAssociationCollection creates a new in-memory association collection
+func newInMemoryAssociationCollection() *inMemoryAssociationCollection {
+	return &inMemoryAssociationCollection{
+		associations: make(map[string]*models.MemoryAssociation),
+		sourceIndex:  make(map[string]map[string]struct{}),
+		targetIndex:  make(map[string]map[string]struct{}),
+	}
+}
+
+// Store saves a single association
+func (iac *inMemoryAssociationCollection) Store(ctx context.Context, association *models.MemoryAssociation) error {
+	iac.mu.Lock()
+	defer iac.mu.Unlock()
+
+	iac.put(association)
+
+	slog.Debug("Stored association", "id", association.ID, "source", association.SourceID, "target", association.TargetID)
+	return nil
+}
+
+// BulkStore saves multiple associations
+func (iac *inMemoryAssociationCollection) BulkStore(ctx context.Context, associations []*models.MemoryAssociation) error {
+	if len(associations) == 0 {
+		return nil
+	}
+
+	iac.mu.Lock()
+	defer iac.mu.Unlock()
+
+	for _, association := range associations {
+		iac.put(association)
+	}
+
+	slog.Debug("Bulk stored associations", "count", len(associations))
+	return nil
+}
+
+// put upserts an association and its index entries; callers must hold the lock
+func (iac *inMemoryAssociationCollection) put(association *models.MemoryAssociation) {
+	if association.ID == "" {
+		association.ID = uuid.New().String()
+	}
+
+	// Drop stale index entries when an association is overwritten
+	if existing, exists := iac.associations[association.ID]; exists {
+		iac.unindex(existing)
+	}
+
+	stored := cloneAssociation(association)
+	iac.associations[stored.ID] = stored
+	addToIndex(iac.sourceIndex, stored.SourceID, stored.ID)
+	addToIndex(iac.targetIndex, stored.TargetID, stored.ID)
+}
+
+// unindex removes an association from the source and target indexes
+func (iac *inMemoryAssociationCollection) unindex(association *models.MemoryAssociation) {
+	removeFromIndex(iac.sourceIndex, association.SourceID, association.ID)
+	removeFromIndex(iac.targetIndex, association.TargetID, association.ID)
+}
+
+// GetByMemoryID retrieves all associations where the memory is the source or target
+func (iac *inMemoryAssociationCollection) GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryAssociation, error) {
+	iac.mu.RLock()
+	defer iac.mu.RUnlock()
+
+	return iac.byMemoryID(memoryID), nil
+}
+
+// byMemoryID collects associations for a memory; callers must hold the lock
+func (iac *inMemoryAssociationCollection) byMemoryID(memoryID string) []*models.MemoryAssociation {
+	seen := make(map[string]struct{})
+	associations := make([]*models.MemoryAssociation, 0)
+
+	for _, index := range []map[string]map[string]struct{}{iac.sourceIndex, iac.targetIndex} {
+		for id := range index[memoryID] {
+			if _, dup := seen[id]; dup {
+				continue
+			}
+			seen[id] = struct{}{}
+			associations = append(associations, cloneAssociation(iac.associations[id]))
+		}
+	}
+
+	sortAssociations(associations)
+	return associations
+}
+
+// GetByMemoryIDs retrieves associations for multiple memories
+func (iac *inMemoryAssociationCollection) GetByMemoryIDs(ctx context.Context, memoryIDs []string) (map[string][]*models.MemoryAssociation, error) {
+	iac.mu.RLock()
+	defer iac.mu.RUnlock()
+
+	result := make(map[string][]*models.MemoryAssociation, len(memoryIDs))
+	for _, memoryID := range memoryIDs {
+		result[memoryID] = iac.byMemoryID(memoryID)
+	}
+
+	return result, nil
+}
+
+// Delete removes specific associations by their IDs
+func (iac *inMemoryAssociationCollection) Delete(ctx context.Context, associationIDs []string) error {
+	iac.mu.Lock()
+	defer iac.mu.Unlock()
+
+	for _, id := range associationIDs {
+		if association, exists := iac.associations[id]; exists {
+			iac.unindex(association)
+			delete(iac.associations, id)
+		}
+	}
+
+	return nil
+}
+
+// DeleteByMemoryID removes all associations for a specific memory
+func (iac *inMemoryAssociationCollection) DeleteByMemoryID(ctx context.Context, memoryID string) error {
+	iac.mu.Lock()
+	defer iac.mu.Unlock()
+
+	for _, association := range iac.byMemoryID(memoryID) {
+		iac.unindex(association)
+		delete(iac.associations, association.ID)
+	}
+
+	return nil
+}
+
+// Count returns the total number of associations
+func (iac *inMemoryAssociationCollection) Count(ctx context.Context) (uint64, error) {
+	iac.mu.RLock()
+	defer iac.mu.RUnlock()
+
+	return uint64(len(iac.associations)), nil
+}
+
+// GetAll retrieves all associations with pagination, oldest first.
+// The cursor is the position of the last association of the previous page.
+func (iac *inMemoryAssociationCollection) GetAll(ctx context.Context, cursor string, limit uint32) (associations []*models.MemoryAssociation, nextCursor string, err error) {
+	iac.mu.RLock()
+	defer iac.mu.RUnlock()
+
+	sorted := make([]*models.MemoryAssociation, 0, len(iac.associations))
+	for _, association := range iac.associations {
+		sorted = append(sorted, association)
+	}
+	sortAssociations(sorted)
+
+	start := 0
+	if cursor != "" {
+		position, err := parsePageCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(sorted), func(i int) bool {
+			return position.afterOldestFirst(sorted[i].CreatedAt, sorted[i].ID)
+		})
+	}
+
+	end := start + int(limit)
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+
+	associations = make([]*models.MemoryAssociation, 0, end-start)
+	for _, association := range sorted[start:end] {
+		associations = append(associations, cloneAssociation(association))
+	}
+
+	if end < len(sorted) && len(associations) > 0 {
+		last := associations[len(associations)-1]
+		nextCursor = pageCursor{createdAt: last.CreatedAt, id: last.ID}.String()
+	}
+
+	return associations, nextCursor, nil
+}
+
+// addToIndex records an association ID under a memory ID
+func addToIndex(index map[string]map[string]struct{}, memoryID, associationID string) {
+	ids, exists := index[memoryID]
+	if !exists {
+		ids = make(map[string]struct{})
+		index[memoryID] = ids
+	}
+	ids[associationID] = struct{}{}
+}
+
+// removeFromIndex drops an association ID from a memory's index entry
+func removeFromIndex(index map[string]map[string]struct{}, memoryID, associationID string) {
+	if ids, exists := index[memoryID]; exists {
+		delete(ids, associationID)
+		if len(ids) == 0 {
+			delete(index, memoryID)
+		}
+	}
+}
+
+// sortAssociations orders associations by creation time, then ID, for stable pagination
+func sortAssociations(associations []*models.MemoryAssociation) {
+	sort.Slice(associations, func(i, j int) bool {
+		if associations[i].CreatedAt.Equal(associations[j].CreatedAt) {
+			return associations[i].ID < associations[j].ID
+		}
+		return associations[i].CreatedAt.Before(associations[j].CreatedAt)
+	})
+}
@@ -0,0 +1,274 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// inMemoryMemoryCollection implements MemoryCollection with process-local maps
+type inMemoryMemoryCollection struct {
+	config      *config.VectorDBConfig
+	collections map[models.MemoryType]string
+	mu          sync.RWMutex
+	stores      map[models.MemoryType]map[string]*models.MemoryEntry
+}
+
+// newInMemoryMemoryCollection creates a new in-memory memory collection
+func newInMemoryMemoryCollection(config *config.VectorDBConfig) *inMemoryMemoryCollection {
+	imc := &inMemoryMemoryCollection{
+		config:      config,
+		collections: memoryCollectionNames(config),
+		stores:      make(map[models.MemoryType]map[string]*models.MemoryEntry),
+	}
+
+	imc.initialize()
+	return imc
+}
+
+// initialize creates missing stores and returns the names of the collections it created
+func (imc *inMemoryMemoryCollection) initialize() []string {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	var created []string
+	for memType, collectionName := range imc.collections {
+		if _, exists := imc.stores[memType]; !exists {
+			imc.stores[memType] = make(map[string]*models.MemoryEntry)
+			created = append(created, collectionName)
+		}
+	}
+	return created
+}
+
+// store returns the backing map for a memory type; callers must hold the lock
+func (imc *inMemoryMemoryCollection) store(memType models.MemoryType) (map[string]*models.MemoryEntry, error) {
+	if _, exists := imc.collections[memType]; !exists {
+		return nil, fmt.Errorf("no collection configured for memory type: %s", memType)
+	}
+	return imc.stores[memType], nil
+}
+
+// Store saves a memory entry to the appropriate collection
+func (imc *inMemoryMemoryCollection) Store(ctx context.Context, entry *models.MemoryEntry) error {
+	if err := validateVectorDimension(imc.config, entry.Embedding); err != nil {
+		return fmt.Errorf("failed to store memory: %w", err)
+	}
+
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	store, err := imc.store(entry.Type)
+	if err != nil {
+		return err
+	}
+
+	// Generate ID if not provided
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+
+	stored := cloneMemoryEntry(entry)
+	stored.Similarity = 0 // Search scores are transient
+	stored.MatchedChunk = ""
+	store[entry.ID] = stored
+
+	slog.Debug("Stored memory", "id", entry.ID, "type", entry.Type, "collection", imc.collections[entry.Type])
+	return nil
+}
+
+// Query performs a brute-force cosine similarity search
+func (imc *inMemoryMemoryCollection) Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	if err := validateVectorDimension(imc.config, vector); err != nil {
+		return nil, fmt.Errorf("failed to search collection %s: %w", imc.collections[memType], err)
+	}
+
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	type scoredEntry struct {
+		entry *models.MemoryEntry
+		score float64
+	}
+
+	scored := make([]scoredEntry, 0, len(store))
+	for _, entry := range store {
+		if !MatchesFilter(entry, filter) {
+			continue
+		}
+		scored = append(scored, scoredEntry{
+			entry: entry,
+			score: cosineSimilarity(vector, entry.Embedding),
+		})
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score == scored[j].score {
+			return scored[i].entry.ID < scored[j].entry.ID
+		}
+		return scored[i].score > scored[j].score
+	})
+
+	if uint64(len(scored)) > limit {
+		scored = scored[:limit]
+	}
+
+	entries := make([]*models.MemoryEntry, len(scored))
+	for i, s := range scored {
+		entries[i] = cloneMemoryEntry(s.entry)
+		entries[i].Similarity = s.score
+	}
+
+	return entries, nil
+}
+
+// KeywordQuery performs a BM25-ranked keyword search over the stored content
+func (imc *inMemoryMemoryCollection) KeywordQuery(ctx context.Context, memType models.MemoryType, query string, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*models.MemoryEntry, 0, len(store))
+	for _, entry := range store {
+		if MatchesFilter(entry, filter) {
+			candidates = append(candidates, entry)
+		}
+	}
+
+	ranked := rankByBM25(uniqueKeywords(query), candidates, len(store), limit)
+
+	entries := make([]*models.MemoryEntry, len(ranked))
+	for i, entry := range ranked {
+		entries[i] = cloneMemoryEntry(entry)
+	}
+
+	return entries, nil
+}
+
+// Retrieve gets a specific memory entry by ID
+func (imc *inMemoryMemoryCollection) Retrieve(ctx context.Context, memType models.MemoryType, id string) (*models.MemoryEntry, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, exists := store[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
+	}
+
+	return cloneMemoryEntry(entry), nil
+}
+
+// GetRecent retrieves recent memories by creation time without similarity search
+func (imc *inMemoryMemoryCollection) GetRecent(ctx context.Context, memType models.MemoryType, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*models.MemoryEntry, 0, min(int(limit), len(store)))
+	for _, entry := range sortedByCreatedAt(store) {
+		if uint32(len(entries)) >= limit {
+			break
+		}
+		if MatchesFilter(entry, filter) {
+			entries = append(entries, cloneMemoryEntry(entry))
+		}
+	}
+
+	return entries, nil
+}
+
+// Count returns the number of memories of a specific type
+func (imc *inMemoryMemoryCollection) Count(ctx context.Context, memType models.MemoryType) (uint64, error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(len(store)), nil
+}
+
+// Delete removes memories by their IDs
+func (imc *inMemoryMemoryCollection) Delete(ctx context.Context, memType models.MemoryType, ids []string) error {
+	imc.mu.Lock()
+	defer imc.mu.Unlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		delete(store, id)
+	}
+
+	return nil
+}
+
+// GetAll retrieves all memories with cursor-based pagination, newest first.
+// The cursor is the position of the last entry of the previous page.
+func (imc *inMemoryMemoryCollection) GetAll(ctx context.Context, memType models.MemoryType, cursor string, limit uint32) (entries []*models.MemoryEntry, nextCursor string, err error) {
+	imc.mu.RLock()
+	defer imc.mu.RUnlock()
+
+	store, err := imc.store(memType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sorted := sortedByCreatedAt(store)
+	start := 0
+	if cursor != "" {
+		position, err := parsePageCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		start = sort.Search(len(sorted), func(i int) bool {
+			return position.afterNewestFirst(sorted[i].CreatedAt, sorted[i].ID)
+		})
+	}
+
+	end := start + int(limit)
+	if end > len(sorted) {
+		end = len(sorted)
+	}
+
+	entries = make([]*models.MemoryEntry, 0, end-start)
+	for _, entry := range sorted[start:end] {
+		entries = append(entries, cloneMemoryEntry(entry))
+	}
+
+	// Only hand out a cursor when more entries remain
+	if end < len(sorted) && len(entries) > 0 {
+		last := entries[len(entries)-1]
+		nextCursor = pageCursor{createdAt: last.CreatedAt, id: last.ID}.String()
+	}
+
+	return entries, nextCursor, nil
+}
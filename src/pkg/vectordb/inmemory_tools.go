@@ -0,0 +1,89 @@
+package vectordb
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// cloneMemoryEntry deep copies a memory entry so stored data is never aliased by callers
+func cloneMemoryEntry(entry *models.MemoryEntry) *models.MemoryEntry {
+	clone := *entry
+	clone.Embedding = slices.Clone(entry.Embedding)
+	clone.AssociationIDs = slices.Clone(entry.AssociationIDs)
+	if entry.Metadata != nil {
+		clone.Metadata = maps.Clone(entry.Metadata)
+	} else {
+		clone.Metadata = make(map[string]any)
+	}
+	return &clone
+}
+
+// cloneAssociation deep copies an association so stored data is never aliased by callers
+func cloneAssociation(association *models.MemoryAssociation) *models.MemoryAssociation {
+	clone := *association
+	if association.Metadata != nil {
+		clone.Metadata = maps.Clone(association.Metadata)
+	} else {
+		clone.Metadata = make(map[string]any)
+	}
+	return &clone
+}
+
+// cloneMemoryVersion deep copies a memory version so stored data is never aliased by callers
+func cloneMemoryVersion(version *models.MemoryVersion) *models.MemoryVersion {
+	clone := *version
+	clone.Metadata = maps.Clone(version.Metadata)
+	return &clone
+}
+
+// sortedByCreatedAt returns entries ordered newest first, breaking ties by ID
+func sortedByCreatedAt(store map[string]*models.MemoryEntry) []*models.MemoryEntry {
+	sorted := make([]*models.MemoryEntry, 0, len(store))
+	for _, entry := range store {
+		sorted = append(sorted, entry)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].CreatedAt.Equal(sorted[j].CreatedAt) {
+			return sorted[i].ID < sorted[j].ID
+		}
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	return sorted
+}
+
+// validateVectorDimension mirrors Qdrant's rejection of vectors with the wrong size
+func validateVectorDimension(config *config.VectorDBConfig, vector []float32) error {
+	if config.VectorDimension > 0 && len(vector) != config.VectorDimension {
+		return fmt.Errorf("vector dimension mismatch: expected %d, got %d", config.VectorDimension, len(vector))
+	}
+	return nil
+}
+
+// cosineSimilarity calculates cosine similarity between two vectors
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0.0 // Cannot compare vectors of different dimensions
+	}
+
+	var dotProduct, normA, normB float64
+	for i := range a {
+		dotProduct += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+
+	// Avoid division by zero
+	if normA == 0.0 || normB == 0.0 {
+		return 0.0
+	}
+
+	return dotProduct / (math.Sqrt(normA) * math.Sqrt(normB))
+}
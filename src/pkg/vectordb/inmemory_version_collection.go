@@ -0,0 +1,66 @@
+package vectordb
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// inMemoryVersionCollection implements VersionCollection with a process-local map keyed by memory ID
+type inMemoryVersionCollection struct {
+	mu       sync.RWMutex
+	versions map[string]map[string]*models.MemoryVersion
+}
+
+// newInMemoryVersionCollection creates a new in-memory version collection
+func newInMemoryVersionCollection() *inMemoryVersionCollection {
+	return &inMemoryVersionCollection{
+		versions: make(map[string]map[string]*models.MemoryVersion),
+	}
+}
+
+// Store saves a memory version
+func (ivc *inMemoryVersionCollection) Store(ctx context.Context, version *models.MemoryVersion) error {
+	if version.ID == "" {
+		version.ID = uuid.New().String()
+	}
+
+	ivc.mu.Lock()
+	defer ivc.mu.Unlock()
+
+	if ivc.versions[version.MemoryID] == nil {
+		ivc.versions[version.MemoryID] = make(map[string]*models.MemoryVersion)
+	}
+	ivc.versions[version.MemoryID][version.ID] = cloneMemoryVersion(version)
+
+	return nil
+}
+
+// GetByMemoryID retrieves every version of a memory ordered by version number
+func (ivc *inMemoryVersionCollection) GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryVersion, error) {
+	ivc.mu.RLock()
+	defer ivc.mu.RUnlock()
+
+	versions := make([]*models.MemoryVersion, 0, len(ivc.versions[memoryID]))
+	for _, version := range ivc.versions[memoryID] {
+		versions = append(versions, cloneMemoryVersion(version))
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// DeleteByMemoryID removes every version of a memory
+func (ivc *inMemoryVersionCollection) DeleteByMemoryID(ctx context.Context, memoryID string) error {
+	ivc.mu.Lock()
+	defer ivc.mu.Unlock()
+
+	delete(ivc.versions, memoryID)
+	return nil
+}
@@ -0,0 +1,57 @@
+package vectordb
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+)
+
+// InMemoryDB implements vector database operations entirely in process.
+// It is intended for tests and offline development where a Qdrant instance
+// is not available; all data is lost when the process exits.
+type InMemoryDB struct {
+	config       *config.VectorDBConfig
+	memories     *inMemoryMemoryCollection
+	associations *inMemoryAssociationCollection
+	versions     *inMemoryVersionCollection
+}
+
+// NewInMemoryDB creates a new in-memory database implementation
+func NewInMemoryDB(config *config.VectorDBConfig) (*InMemoryDB, error) {
+	return &InMemoryDB{
+		config:       config,
+		memories:     newInMemoryMemoryCollection(config),
+		associations: newInMemoryAssociationCollection(),
+		versions:     newInMemoryVersionCollection(),
+	}, nil
+}
+
+// Initialize ensures a store exists for every configured memory type
+func (db *InMemoryDB) Initialize(ctx context.Context) error {
+	created := db.memories.initialize()
+	for _, collectionName := range created {
+		slog.Info("Created in-memory collection", "collection", collectionName)
+	}
+	return nil
+}
+
+// HealthCheck always succeeds for the in-memory database
+func (db *InMemoryDB) HealthCheck(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Memories returns the memory collection interface
+func (db *InMemoryDB) Memories() MemoryCollection {
+	return db.memories
+}
+
+// Associations returns the association collection interface
+func (db *InMemoryDB) Associations() AssociationCollection {
+	return db.associations
+}
+
+// Versions returns the memory version collection interface
+func (db *InMemoryDB) Versions() VersionCollection {
+	return db.versions
+}
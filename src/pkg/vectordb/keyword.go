@@ -0,0 +1,121 @@
+package vectordb
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// BM25 tuning parameters (standard Okapi defaults)
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// keywordPageSize is how many matching points a remote store returns per page of BM25 candidates
+const keywordPageSize = 1000
+
+// tokenizeKeywords lowercases text and splits it into alphanumeric terms.
+// Underscores are kept so identifiers like snake_case names stay intact.
+func tokenizeKeywords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+	})
+}
+
+// uniqueKeywords tokenizes text and removes duplicate terms, preserving order
+func uniqueKeywords(text string) []string {
+	terms := tokenizeKeywords(text)
+	seen := make(map[string]struct{}, len(terms))
+	unique := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if _, dup := seen[term]; dup {
+			continue
+		}
+		seen[term] = struct{}{}
+		unique = append(unique, term)
+	}
+	return unique
+}
+
+// rankByBM25 scores candidate memories against query terms with Okapi BM25 and
+// returns the best matches. totalDocs is the size of the whole collection and is
+// used for IDF, so candidates may be a pre-filtered subset of the collection.
+func rankByBM25(terms []string, candidates []*models.MemoryEntry, totalDocs int, limit uint64) []*models.MemoryEntry {
+	if len(terms) == 0 || len(candidates) == 0 {
+		return []*models.MemoryEntry{}
+	}
+	if totalDocs < len(candidates) {
+		totalDocs = len(candidates)
+	}
+
+	termFrequencies := make([]map[string]int, len(candidates))
+	docLengths := make([]int, len(candidates))
+	docFrequency := make(map[string]int, len(terms))
+	totalLength := 0
+
+	for i, candidate := range candidates {
+		tokens := tokenizeKeywords(candidate.Content)
+		frequencies := make(map[string]int)
+		for _, token := range tokens {
+			frequencies[token]++
+		}
+		termFrequencies[i] = frequencies
+		docLengths[i] = len(tokens)
+		totalLength += len(tokens)
+
+		for _, term := range terms {
+			if frequencies[term] > 0 {
+				docFrequency[term]++
+			}
+		}
+	}
+
+	avgLength := float64(totalLength) / float64(len(candidates))
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	type scoredEntry struct {
+		entry *models.MemoryEntry
+		score float64
+	}
+
+	scored := make([]scoredEntry, 0, len(candidates))
+	for i, candidate := range candidates {
+		score := 0.0
+		for _, term := range terms {
+			tf := float64(termFrequencies[i][term])
+			if tf == 0 {
+				continue
+			}
+			n := float64(docFrequency[term])
+			idf := math.Log((float64(totalDocs)-n+0.5)/(n+0.5) + 1)
+			norm := 1 - bm25B + bm25B*float64(docLengths[i])/avgLength
+			score += idf * (tf * (bm25K1 + 1)) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			scored = append(scored, scoredEntry{entry: candidate, score: score})
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score == scored[j].score {
+			return scored[i].entry.ID < scored[j].entry.ID
+		}
+		return scored[i].score > scored[j].score
+	})
+
+	if uint64(len(scored)) > limit {
+		scored = scored[:limit]
+	}
+
+	ranked := make([]*models.MemoryEntry, len(scored))
+	for i, s := range scored {
+		ranked[i] = s.entry
+	}
+	return ranked
+}
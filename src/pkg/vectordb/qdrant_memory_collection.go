@@ -23,12 +23,7 @@ func newQdrantMemoryCollection(client *qdrant.Client, config *config.VectorDBCon
 	qmc := &qdrantMemoryCollection{
 		client:      client,
 		config:      config,
-		collections: make(map[models.MemoryType]string),
-	}
-
-	// Map memory types to collection names
-	for memType, collectionName := range config.MemoryCollections {
-		qmc.collections[models.MemoryType(memType)] = collectionName
+		collections: memoryCollectionNames(config),
 	}
 
 	return qmc
@@ -69,15 +64,21 @@ func (qmc *qdrantMemoryCollection) Store(ctx context.Context, entry *models.Memo
 }
 
 // Query performs a vector similarity search
-func (qmc *qdrantMemoryCollection) Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64) ([]*models.MemoryEntry, error) {
+func (qmc *qdrantMemoryCollection) Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
 	collectionName, exists := qmc.collections[memType]
 	if !exists {
 		return nil, fmt.Errorf("no collection configured for memory type: %s", memType)
 	}
 
+	qdrantFilter, err := memoryFilterToQdrant(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory filter: %w", err)
+	}
+
 	response, err := qmc.client.Query(ctx, &qdrant.QueryPoints{
 		CollectionName: collectionName,
 		Query:          qdrant.NewQuery(vector...),
+		Filter:         qdrantFilter,
 		Limit:          &limit,
 		WithPayload: &qdrant.WithPayloadSelector{
 			SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true},
@@ -104,6 +105,74 @@ func (qmc *qdrantMemoryCollection) Query(ctx context.Context, memType models.Mem
 	return entries, nil
 }
 
+// KeywordQuery performs a keyword search. Qdrant's full-text filter narrows the
+// collection to points containing any query term and BM25 ranks all of them in process.
+func (qmc *qdrantMemoryCollection) KeywordQuery(ctx context.Context, memType models.MemoryType, query string, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	collectionName, exists := qmc.collections[memType]
+	if !exists {
+		return nil, fmt.Errorf("no collection configured for memory type: %s", memType)
+	}
+
+	terms := uniqueKeywords(query)
+	if len(terms) == 0 {
+		return []*models.MemoryEntry{}, nil
+	}
+
+	should := make([]*qdrant.Condition, len(terms))
+	for i, term := range terms {
+		should[i] = qdrant.NewMatchText("content", term)
+	}
+	textFilter := &qdrant.Filter{Should: should}
+
+	qdrantFilter, err := memoryFilterToQdrant(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory filter: %w", err)
+	}
+	if qdrantFilter != nil {
+		textFilter.Must = []*qdrant.Condition{qdrant.NewFilterAsCondition(qdrantFilter)}
+	}
+
+	// Text matches come back unordered, so every match is paged in before BM25 ranks them.
+	// Vectors are left out because ranking only needs the content.
+	pageSize := uint32(keywordPageSize)
+	request := &qdrant.ScrollPoints{
+		CollectionName: collectionName,
+		Filter:         textFilter,
+		Limit:          &pageSize,
+		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true}},
+		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: false}},
+	}
+
+	var candidates []*models.MemoryEntry
+	for {
+		points, next, err := qmc.client.ScrollAndOffset(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to keyword search collection %s: %w", collectionName, err)
+		}
+
+		for _, point := range points {
+			entry, err := retrievedPointToMemoryEntry(point)
+			if err != nil {
+				slog.Warn("Failed to convert retrieved point to memory entry", "error", err)
+				continue
+			}
+			candidates = append(candidates, entry)
+		}
+
+		if next == nil || len(points) == 0 {
+			break
+		}
+		request.Offset = next
+	}
+
+	total, err := qmc.Count(ctx, memType)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankByBM25(terms, candidates, int(total), limit), nil
+}
+
 // Retrieve gets a specific memory entry by ID
 func (qmc *qdrantMemoryCollection) Retrieve(ctx context.Context, memType models.MemoryType, id string) (*models.MemoryEntry, error) {
 	collectionName, exists := qmc.collections[memType]
@@ -123,22 +192,28 @@ func (qmc *qdrantMemoryCollection) Retrieve(ctx context.Context, memType models.
 	}
 
 	if len(response) == 0 {
-		return nil, fmt.Errorf("memory not found: %s", id)
+		return nil, fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
 	}
 
 	return retrievedPointToMemoryEntry(response[0])
 }
 
 // GetRecent retrieves recent memories by creation time without similarity search
-func (qmc *qdrantMemoryCollection) GetRecent(ctx context.Context, memType models.MemoryType, limit uint32) ([]*models.MemoryEntry, error) {
+func (qmc *qdrantMemoryCollection) GetRecent(ctx context.Context, memType models.MemoryType, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
 	collectionName, exists := qmc.collections[memType]
 	if !exists {
 		return nil, fmt.Errorf("no collection configured for memory type: %s", memType)
 	}
 
+	qdrantFilter, err := memoryFilterToQdrant(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid memory filter: %w", err)
+	}
+
 	direction := qdrant.Direction_Desc
 	response, err := qmc.client.Scroll(ctx, &qdrant.ScrollPoints{
 		CollectionName: collectionName,
+		Filter:         qdrantFilter,
 		Limit:          &limit,
 		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true}},
 		WithVectors:    &qdrant.WithVectorsSelector{SelectorOptions: &qdrant.WithVectorsSelector_Enable{Enable: true}},
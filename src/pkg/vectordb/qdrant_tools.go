@@ -3,6 +3,7 @@ package vectordb
 import (
 	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -62,15 +63,8 @@ func retrievedPointToMemoryEntry(retrievedPoint *qdrant.RetrievedPoint) (*models
 			if key == "content" || key == "type" || key == "created_at" || key == "accessed_at" || key == "strength" || key == "association_ids" {
 				continue
 			}
-			switch v := value.Kind.(type) {
-			case *qdrant.Value_StringValue:
-				entry.Metadata[key] = v.StringValue
-			case *qdrant.Value_IntegerValue:
-				entry.Metadata[key] = v.IntegerValue
-			case *qdrant.Value_DoubleValue:
-				entry.Metadata[key] = v.DoubleValue
-			case *qdrant.Value_BoolValue:
-				entry.Metadata[key] = v.BoolValue
+			if v, ok := qdrantValueToAny(value); ok {
+				entry.Metadata[key] = v
 			}
 		}
 	}
@@ -81,8 +75,9 @@ func retrievedPointToMemoryEntry(retrievedPoint *qdrant.RetrievedPoint) (*models
 // scoredPointToMemoryEntry converts a Qdrant ScoredPoint to a memory entry
 func scoredPointToMemoryEntry(scoredPoint *qdrant.ScoredPoint) (*models.MemoryEntry, error) {
 	entry := &models.MemoryEntry{
-		ID:       scoredPoint.Id.GetUuid(),
-		Metadata: make(map[string]any),
+		ID:         scoredPoint.Id.GetUuid(),
+		Metadata:   make(map[string]any),
+		Similarity: float64(scoredPoint.Score),
 	}
 
 	// Extract vector
@@ -129,15 +124,8 @@ func scoredPointToMemoryEntry(scoredPoint *qdrant.ScoredPoint) (*models.MemoryEn
 			if key == "content" || key == "type" || key == "created_at" || key == "accessed_at" || key == "strength" || key == "association_ids" {
 				continue
 			}
-			switch v := value.Kind.(type) {
-			case *qdrant.Value_StringValue:
-				entry.Metadata[key] = v.StringValue
-			case *qdrant.Value_IntegerValue:
-				entry.Metadata[key] = v.IntegerValue
-			case *qdrant.Value_DoubleValue:
-				entry.Metadata[key] = v.DoubleValue
-			case *qdrant.Value_BoolValue:
-				entry.Metadata[key] = v.BoolValue
+			if v, ok := qdrantValueToAny(value); ok {
+				entry.Metadata[key] = v
 			}
 		}
 	}
@@ -191,12 +179,157 @@ func anyToQdrantValue(v any) *qdrant.Value {
 		return &qdrant.Value{Kind: &qdrant.Value_DoubleValue{DoubleValue: val}}
 	case bool:
 		return &qdrant.Value{Kind: &qdrant.Value_BoolValue{BoolValue: val}}
+	case []string:
+		values := make([]*qdrant.Value, len(val))
+		for i, item := range val {
+			values[i] = anyToQdrantValue(item)
+		}
+		return &qdrant.Value{Kind: &qdrant.Value_ListValue{ListValue: &qdrant.ListValue{Values: values}}}
+	case []any:
+		values := make([]*qdrant.Value, len(val))
+		for i, item := range val {
+			values[i] = anyToQdrantValue(item)
+		}
+		return &qdrant.Value{Kind: &qdrant.Value_ListValue{ListValue: &qdrant.ListValue{Values: values}}}
+	case map[string]any:
+		fields := make(map[string]*qdrant.Value, len(val))
+		for key, item := range val {
+			fields[key] = anyToQdrantValue(item)
+		}
+		return &qdrant.Value{Kind: &qdrant.Value_StructValue{StructValue: &qdrant.Struct{Fields: fields}}}
 	default:
 		// Fallback: convert to string
 		return &qdrant.Value{Kind: &qdrant.Value_StringValue{StringValue: fmt.Sprintf("%v", val)}}
 	}
 }
 
+// qdrantValueToAny converts a Qdrant Value back to a Go value, keeping lists as []any and structs as map[string]any
+func qdrantValueToAny(value *qdrant.Value) (any, bool) {
+	switch v := value.Kind.(type) {
+	case *qdrant.Value_StringValue:
+		return v.StringValue, true
+	case *qdrant.Value_IntegerValue:
+		return v.IntegerValue, true
+	case *qdrant.Value_DoubleValue:
+		return v.DoubleValue, true
+	case *qdrant.Value_BoolValue:
+		return v.BoolValue, true
+	case *qdrant.Value_ListValue:
+		items := make([]any, 0, len(v.ListValue.GetValues()))
+		for _, item := range v.ListValue.GetValues() {
+			if converted, ok := qdrantValueToAny(item); ok {
+				items = append(items, converted)
+			}
+		}
+		return items, true
+	case *qdrant.Value_StructValue:
+		fields := make(map[string]any, len(v.StructValue.GetFields()))
+		for key, item := range v.StructValue.GetFields() {
+			if converted, ok := qdrantValueToAny(item); ok {
+				fields[key] = converted
+			}
+		}
+		return fields, true
+	default:
+		return nil, false
+	}
+}
+
+// memoryFilterToQdrant translates a memory filter into Qdrant filter conditions.
+// It returns nil for an empty filter so requests stay unfiltered.
+func memoryFilterToQdrant(filter *models.MemoryFilter) (*qdrant.Filter, error) {
+	if filter.IsEmpty() {
+		return nil, nil
+	}
+
+	result := &qdrant.Filter{}
+	groups := []struct {
+		conditions []models.FilterCondition
+		target     *[]*qdrant.Condition
+	}{
+		{filter.Must, &result.Must},
+		{filter.Should, &result.Should},
+		{filter.MustNot, &result.MustNot},
+	}
+
+	for _, group := range groups {
+		for _, condition := range group.conditions {
+			converted, err := filterConditionToQdrant(condition)
+			if err != nil {
+				return nil, err
+			}
+			*group.target = append(*group.target, converted)
+		}
+	}
+
+	return result, nil
+}
+
+// filterConditionToQdrant translates a single filter condition
+func filterConditionToQdrant(condition models.FilterCondition) (*qdrant.Condition, error) {
+	if condition.Filter != nil {
+		nested, err := memoryFilterToQdrant(condition.Filter)
+		if err != nil {
+			return nil, err
+		}
+		if nested == nil {
+			nested = &qdrant.Filter{}
+		}
+		return qdrant.NewFilterAsCondition(nested), nil
+	}
+
+	switch {
+	case condition.Range != nil:
+		return qdrant.NewRange(condition.Field, &qdrant.Range{
+			Gt:  condition.Range.GT,
+			Gte: condition.Range.GTE,
+			Lt:  condition.Range.LT,
+			Lte: condition.Range.LTE,
+		}), nil
+
+	case condition.Equals != nil:
+		switch v := condition.Equals.(type) {
+		case string:
+			return qdrant.NewMatchKeyword(condition.Field, v), nil
+		case bool:
+			return qdrant.NewMatchBool(condition.Field, v), nil
+		}
+		number, ok := filterNumber(condition.Equals)
+		if !ok {
+			return nil, fmt.Errorf("unsupported equals value for field %s: %v", condition.Field, condition.Equals)
+		}
+		if number == math.Trunc(number) {
+			return qdrant.NewMatchInt(condition.Field, int64(number)), nil
+		}
+		// Qdrant only matches integers exactly, so express float equality as a closed range
+		return qdrant.NewRange(condition.Field, &qdrant.Range{Gte: &number, Lte: &number}), nil
+
+	case condition.In != nil:
+		keywords := make([]string, 0, len(condition.In))
+		integers := make([]int64, 0, len(condition.In))
+		for _, item := range condition.In {
+			if s, ok := item.(string); ok {
+				keywords = append(keywords, s)
+				continue
+			}
+			number, ok := filterNumber(item)
+			if !ok || number != math.Trunc(number) {
+				return nil, fmt.Errorf("unsupported in value for field %s: %v", condition.Field, item)
+			}
+			integers = append(integers, int64(number))
+		}
+		if len(keywords) > 0 && len(integers) > 0 {
+			return nil, fmt.Errorf("in values for field %s must all be strings or all be integers", condition.Field)
+		}
+		if len(integers) > 0 {
+			return qdrant.NewMatchInts(condition.Field, integers...), nil
+		}
+		return qdrant.NewMatchKeywords(condition.Field, keywords...), nil
+	}
+
+	return nil, fmt.Errorf("filter condition on %s has no matcher", condition.Field)
+}
+
 // collectionExists checks if a collection exists
 func collectionExists(ctx context.Context, client *qdrant.Client, name string) (bool, error) {
 	response, err := client.ListCollections(ctx)
@@ -250,6 +383,66 @@ func createPayloadIndex(ctx context.Context, client *qdrant.Client, collectionNa
 	return err
 }
 
+// createTextIndex creates a full-text payload index on the content field.
+// Without it Qdrant falls back to substring matching for text conditions.
+func createTextIndex(ctx context.Context, client *qdrant.Client, collectionName string) error {
+	fieldType := qdrant.FieldType_FieldTypeText
+	lowercase := true
+
+	_, err := client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: collectionName,
+		FieldName:      "content",
+		FieldType:      &fieldType,
+		FieldIndexParams: &qdrant.PayloadIndexParams{
+			IndexParams: &qdrant.PayloadIndexParams_TextIndexParams{
+				TextIndexParams: &qdrant.TextIndexParams{
+					Tokenizer: qdrant.TokenizerType_Word,
+					Lowercase: &lowercase,
+				},
+			},
+		},
+	})
+
+	return err
+}
+
+// filterPayloadIndexes lists the payload fields indexed to support memory filters
+var filterPayloadIndexes = map[string]qdrant.FieldType{
+	"source":          qdrant.FieldType_FieldTypeKeyword,
+	"tags":            qdrant.FieldType_FieldTypeKeyword,
+	"type":            qdrant.FieldType_FieldTypeKeyword,
+	"captured_at":     qdrant.FieldType_FieldTypeInteger,
+	"consolidated_at": qdrant.FieldType_FieldTypeInteger,
+}
+
+// createFilterIndexes creates the payload indexes used by memory filters.
+// Qdrant treats re-creating an existing index with the same schema as a no-op.
+func createFilterIndexes(ctx context.Context, client *qdrant.Client, collectionName string) error {
+	for field, fieldType := range filterPayloadIndexes {
+		if _, err := client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+			CollectionName: collectionName,
+			FieldName:      field,
+			FieldType:      &fieldType,
+		}); err != nil {
+			return fmt.Errorf("failed to index %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// createKeywordIndex creates a keyword payload index for exact-match lookups on a field
+func createKeywordIndex(ctx context.Context, client *qdrant.Client, collectionName, field string) error {
+	fieldType := qdrant.FieldType_FieldTypeKeyword
+
+	_, err := client.CreateFieldIndex(ctx, &qdrant.CreateFieldIndexCollection{
+		CollectionName: collectionName,
+		FieldName:      field,
+		FieldType:      &fieldType,
+	})
+
+	return err
+}
+
 // parseGRPCAddress parses a gRPC address in host:port format
 // Supports both "host:port" and "host" (defaults to port 6334)
 func parseGRPCAddress(address string) (host string, port int) {
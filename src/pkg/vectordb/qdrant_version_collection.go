@@ -0,0 +1,137 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+	qdrant "github.com/qdrant/go-client/qdrant"
+)
+
+// qdrantVersionLimit caps how many revisions of a single memory are read back
+const qdrantVersionLimit = 1000
+
+// qdrantVersionCollection implements VersionCollection for Qdrant
+type qdrantVersionCollection struct {
+	client         *qdrant.Client
+	collectionName string
+}
+
+// newQdrantVersionCollection creates a new Qdrant version collection
+func newQdrantVersionCollection(client *qdrant.Client, collectionName string) *qdrantVersionCollection {
+	return &qdrantVersionCollection{
+		client:         client,
+		collectionName: collectionName,
+	}
+}
+
+// Store saves a memory version
+func (qvc *qdrantVersionCollection) Store(ctx context.Context, version *models.MemoryVersion) error {
+	if version.ID == "" {
+		version.ID = uuid.New().String()
+	}
+
+	// Versions don't need vector embeddings, use zero vector
+	zeroVector := make([]float32, 1) // Minimal 1D vector
+
+	_, err := qvc.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: qvc.collectionName,
+		Points: []*qdrant.PointStruct{
+			{
+				Id:      &qdrant.PointId{PointIdOptions: &qdrant.PointId_Uuid{Uuid: version.ID}},
+				Vectors: &qdrant.Vectors{VectorsOptions: &qdrant.Vectors_Vector{Vector: &qdrant.Vector{Data: zeroVector}}},
+				Payload: versionToQdrantPayload(version),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store memory version: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMemoryID retrieves every version of a memory ordered by version number
+func (qvc *qdrantVersionCollection) GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryVersion, error) {
+	limit := uint32(qdrantVersionLimit)
+	response, err := qvc.client.Scroll(ctx, &qdrant.ScrollPoints{
+		CollectionName: qvc.collectionName,
+		Filter:         &qdrant.Filter{Must: []*qdrant.Condition{qdrant.NewMatchKeyword("memory_id", memoryID)}},
+		Limit:          &limit,
+		WithPayload:    &qdrant.WithPayloadSelector{SelectorOptions: &qdrant.WithPayloadSelector_Enable{Enable: true}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions for memory %s: %w", memoryID, err)
+	}
+
+	versions := make([]*models.MemoryVersion, 0, len(response))
+	for _, point := range response {
+		versions = append(versions, qdrantPointToVersion(point))
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+
+	return versions, nil
+}
+
+// DeleteByMemoryID removes every version of a memory
+func (qvc *qdrantVersionCollection) DeleteByMemoryID(ctx context.Context, memoryID string) error {
+	_, err := qvc.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: qvc.collectionName,
+		Points: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+			Must: []*qdrant.Condition{qdrant.NewMatchKeyword("memory_id", memoryID)},
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete versions for memory %s: %w", memoryID, err)
+	}
+
+	slog.Debug("Deleted memory versions", "memory_id", memoryID)
+	return nil
+}
+
+// versionToQdrantPayload converts a MemoryVersion to Qdrant payload
+func versionToQdrantPayload(version *models.MemoryVersion) map[string]*qdrant.Value {
+	payload := map[string]*qdrant.Value{
+		"memory_id": qdrant.NewValueString(version.MemoryID),
+		"version":   qdrant.NewValueInt(int64(version.Version)),
+		"content":   qdrant.NewValueString(version.Content),
+		"edited_at": qdrant.NewValueInt(version.EditedAt.Unix()),
+		"edited_by": qdrant.NewValueString(version.EditedBy),
+	}
+
+	if version.Metadata != nil {
+		payload["metadata"] = anyToQdrantValue(version.Metadata)
+	}
+
+	return payload
+}
+
+// qdrantPointToVersion converts a Qdrant point to MemoryVersion
+func qdrantPointToVersion(point *qdrant.RetrievedPoint) *models.MemoryVersion {
+	payload := point.GetPayload()
+	version := &models.MemoryVersion{
+		ID:       point.GetId().GetUuid(),
+		MemoryID: payload["memory_id"].GetStringValue(),
+		Version:  int(payload["version"].GetIntegerValue()),
+		Content:  payload["content"].GetStringValue(),
+		EditedBy: payload["edited_by"].GetStringValue(),
+	}
+
+	if editedAt := payload["edited_at"].GetIntegerValue(); editedAt != 0 {
+		version.EditedAt = timeFromUnix(editedAt)
+	}
+
+	if value := payload["metadata"]; value != nil {
+		if metadata, ok := qdrantValueToAny(value); ok {
+			version.Metadata, _ = metadata.(map[string]any)
+		}
+	}
+
+	return version
+}
@@ -18,6 +18,7 @@ type QdrantDB struct {
 	memoryCollections map[models.MemoryType]string
 	memories         *qdrantMemoryCollection
 	associations     *qdrantAssociationCollection
+	versions         *qdrantVersionCollection
 }
 
 // NewQdrantDB creates a new Qdrant database implementation
@@ -36,17 +37,13 @@ func NewQdrantDB(config *config.VectorDBConfig) (*QdrantDB, error) {
 	qc := &QdrantDB{
 		client:           client,
 		config:           config,
-		memoryCollections: make(map[models.MemoryType]string),
-	}
-
-	// Map memory types to collection names
-	for memType, collectionName := range config.MemoryCollections {
-		qc.memoryCollections[models.MemoryType(memType)] = collectionName
+		memoryCollections: memoryCollectionNames(config),
 	}
 
 	// Initialize collections
 	qc.memories = newQdrantMemoryCollection(client, config)
 	qc.associations = newQdrantAssociationCollection(client, config.AssociationsCollection)
+	qc.versions = newQdrantVersionCollection(client, config.VersionsCollection)
 
 	return qc, nil
 }
@@ -71,6 +68,17 @@ func (qc *QdrantDB) Initialize(ctx context.Context) error {
 			}
 			slog.Info("Created payload index for created_at", "collection", collectionName)
 		}
+
+		// Ensure the full-text index for KeywordQuery() on every run so collections created
+		// before keyword search existed pick it up
+		if err := createTextIndex(ctx, qc.client, collectionName); err != nil {
+			return fmt.Errorf("failed to create text index for collection %s: %w", collectionName, err)
+		}
+
+		// Ensure filter indexes on every run so existing collections pick them up
+		if err := createFilterIndexes(ctx, qc.client, collectionName); err != nil {
+			return fmt.Errorf("failed to create filter indexes for collection %s: %w", collectionName, err)
+		}
 	}
 
 	// Initialize association collection
@@ -88,6 +96,24 @@ func (qc *QdrantDB) Initialize(ctx context.Context) error {
 		slog.Info("Created association collection", "collection", associationCollectionName)
 	}
 
+	// Initialize version collection
+	versionCollectionName := qc.config.VersionsCollection
+	exists, err = collectionExists(ctx, qc.client, versionCollectionName)
+	if err != nil {
+		return fmt.Errorf("failed to check version collection %s: %w", versionCollectionName, err)
+	}
+
+	if !exists {
+		// Versions are looked up by memory ID only, so a minimal vector is enough
+		if err := createCollection(ctx, qc.client, versionCollectionName, 1, qc.config.OnDiskPayload); err != nil {
+			return fmt.Errorf("failed to create version collection %s: %w", versionCollectionName, err)
+		}
+		if err := createKeywordIndex(ctx, qc.client, versionCollectionName, "memory_id"); err != nil {
+			return fmt.Errorf("failed to create memory_id index for collection %s: %w", versionCollectionName, err)
+		}
+		slog.Info("Created version collection", "collection", versionCollectionName)
+	}
+
 	return nil
 }
 
@@ -349,3 +375,8 @@ func (qc *QdrantDB) Memories() MemoryCollection {
 func (qc *QdrantDB) Associations() AssociationCollection {
 	return qc.associations
 }
+
+// Versions returns the memory version collection interface
+func (qc *QdrantDB) Versions() VersionCollection {
+	return qc.versions
+}
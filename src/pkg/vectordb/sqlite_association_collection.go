@@ -0,0 +1,209 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// sqliteAssociationCollection implements AssociationCollection with a relational table
+type sqliteAssociationCollection struct {
+	db    *sql.DB
+	table string
+}
+
+// newSQLiteAssociationCollection creates a new SQLite association collection
+func newSQLiteAssociationCollection(db *sql.DB, table string) *sqliteAssociationCollection {
+	return &sqliteAssociationCollection{
+		db:    db,
+		table: table,
+	}
+}
+
+// Store saves a single association
+func (sac *sqliteAssociationCollection) Store(ctx context.Context, association *models.MemoryAssociation) error {
+	if err := sac.BulkStore(ctx, []*models.MemoryAssociation{association}); err != nil {
+		return fmt.Errorf("failed to store association: %w", err)
+	}
+
+	slog.Debug("Stored association", "id", association.ID, "source", association.SourceID, "target", association.TargetID)
+	return nil
+}
+
+// BulkStore saves multiple associations in a single transaction
+func (sac *sqliteAssociationCollection) BulkStore(ctx context.Context, associations []*models.MemoryAssociation) error {
+	if len(associations) == 0 {
+		return nil
+	}
+
+	tx, err := sac.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		fmt.Sprintf(`INSERT OR REPLACE INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, quoteIdentifier(sac.table), sqliteAssociationColumns))
+	if err != nil {
+		return fmt.Errorf("failed to prepare association insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, association := range associations {
+		if association.ID == "" {
+			association.ID = uuid.New().String()
+		}
+
+		metadata, err := encodeJSON(association.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to encode association metadata: %w", err)
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			association.ID, association.SourceID, association.TargetID, string(association.Type), association.Strength,
+			unixNanoOrZero(association.CreatedAt), unixNanoOrZero(association.UpdatedAt), metadata); err != nil {
+			return fmt.Errorf("failed to bulk store associations: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to bulk store associations: %w", err)
+	}
+
+	slog.Debug("Bulk stored associations", "count", len(associations))
+	return nil
+}
+
+// GetByMemoryID retrieves all associations where the memory is the source or target
+func (sac *sqliteAssociationCollection) GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryAssociation, error) {
+	rows, err := sac.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s WHERE source_id = ? OR target_id = ? ORDER BY created_at, id`,
+			sqliteAssociationColumns, quoteIdentifier(sac.table)),
+		memoryID, memoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get associations for memory %s: %w", memoryID, err)
+	}
+	defer rows.Close()
+
+	return collectAssociations(rows)
+}
+
+// GetByMemoryIDs retrieves associations for multiple memories
+func (sac *sqliteAssociationCollection) GetByMemoryIDs(ctx context.Context, memoryIDs []string) (map[string][]*models.MemoryAssociation, error) {
+	result := make(map[string][]*models.MemoryAssociation, len(memoryIDs))
+
+	for _, memoryID := range memoryIDs {
+		associations, err := sac.GetByMemoryID(ctx, memoryID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get associations for memory %s: %w", memoryID, err)
+		}
+		result[memoryID] = associations
+	}
+
+	return result, nil
+}
+
+// Delete removes specific associations by their IDs
+func (sac *sqliteAssociationCollection) Delete(ctx context.Context, associationIDs []string) error {
+	if len(associationIDs) == 0 {
+		return nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(associationIDs)), ", ")
+	args := make([]any, len(associationIDs))
+	for i, id := range associationIDs {
+		args[i] = id
+	}
+
+	if _, err := sac.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE id IN (%s)`, quoteIdentifier(sac.table), placeholders), args...); err != nil {
+		return fmt.Errorf("failed to delete associations: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByMemoryID removes all associations for a specific memory
+func (sac *sqliteAssociationCollection) DeleteByMemoryID(ctx context.Context, memoryID string) error {
+	if _, err := sac.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE source_id = ? OR target_id = ?`, quoteIdentifier(sac.table)),
+		memoryID, memoryID); err != nil {
+		return fmt.Errorf("failed to delete associations for memory %s: %w", memoryID, err)
+	}
+
+	return nil
+}
+
+// Count returns the total number of associations
+func (sac *sqliteAssociationCollection) Count(ctx context.Context) (uint64, error) {
+	var count uint64
+	if err := sac.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, quoteIdentifier(sac.table))).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count associations: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetAll retrieves all associations with keyset pagination ordered by creation time.
+// The cursor is the position of the last association of the previous page.
+func (sac *sqliteAssociationCollection) GetAll(ctx context.Context, cursor string, limit uint32) (associations []*models.MemoryAssociation, nextCursor string, err error) {
+	quoted := quoteIdentifier(sac.table)
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY created_at, id LIMIT ?`, sqliteAssociationColumns, quoted)
+	args := []any{int64(limit) + 1}
+
+	if cursor != "" {
+		position, err := parsePageCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = fmt.Sprintf(`SELECT %s FROM %s
+			WHERE created_at > ? OR (created_at = ? AND id > ?)
+			ORDER BY created_at, id LIMIT ?`, sqliteAssociationColumns, quoted)
+		nanos := unixNanoOrZero(position.createdAt)
+		args = []any{nanos, nanos, position.id, int64(limit) + 1}
+	}
+
+	rows, err := sac.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scroll associations: %w", err)
+	}
+	defer rows.Close()
+
+	associations, err = collectAssociations(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// One extra row was requested to detect whether another page exists
+	if len(associations) > int(limit) {
+		associations = associations[:limit]
+		if len(associations) > 0 {
+			last := associations[len(associations)-1]
+			nextCursor = pageCursor{createdAt: last.CreatedAt, id: last.ID}.String()
+		}
+	}
+
+	return associations, nextCursor, nil
+}
+
+// collectAssociations scans all association rows, skipping rows that fail to convert
+func collectAssociations(rows *sql.Rows) ([]*models.MemoryAssociation, error) {
+	associations := make([]*models.MemoryAssociation, 0)
+	for rows.Next() {
+		association, err := scanAssociation(rows)
+		if err != nil {
+			slog.Warn("Failed to convert row to association", "error", err)
+			continue
+		}
+		associations = append(associations, association)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read association rows: %w", err)
+	}
+	return associations, nil
+}
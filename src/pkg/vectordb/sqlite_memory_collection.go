@@ -0,0 +1,374 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// sqliteMemoryCollection implements MemoryCollection for SQLite
+type sqliteMemoryCollection struct {
+	db          *sql.DB
+	config      *config.VectorDBConfig
+	collections map[models.MemoryType]string
+}
+
+// newSQLiteMemoryCollection creates a new SQLite memory collection
+func newSQLiteMemoryCollection(db *sql.DB, config *config.VectorDBConfig) *sqliteMemoryCollection {
+	smc := &sqliteMemoryCollection{
+		db:          db,
+		config:      config,
+		collections: memoryCollectionNames(config),
+	}
+
+	return smc
+}
+
+// table returns the table name for a memory type
+func (smc *sqliteMemoryCollection) table(memType models.MemoryType) (string, error) {
+	table, exists := smc.collections[memType]
+	if !exists {
+		return "", fmt.Errorf("no collection configured for memory type: %s", memType)
+	}
+	return table, nil
+}
+
+// Store saves a memory entry and refreshes its full-text index row
+func (smc *sqliteMemoryCollection) Store(ctx context.Context, entry *models.MemoryEntry) error {
+	table, err := smc.table(entry.Type)
+	if err != nil {
+		return err
+	}
+
+	if err := validateVectorDimension(smc.config, entry.Embedding); err != nil {
+		return fmt.Errorf("failed to store memory: %w", err)
+	}
+
+	// Generate ID if not provided
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+
+	metadata, err := encodeJSON(entry.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	associationIDs := entry.AssociationIDs
+	if associationIDs == nil {
+		associationIDs = []string{}
+	}
+	associations, err := encodeJSON(associationIDs)
+	if err != nil {
+		return fmt.Errorf("failed to encode association IDs: %w", err)
+	}
+
+	tx, err := smc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		fmt.Sprintf(`INSERT OR REPLACE INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, quoteIdentifier(table), sqliteMemoryColumns),
+		entry.ID, string(entry.Type), entry.Content, encodeEmbedding(entry.Embedding), metadata,
+		unixNanoOrZero(entry.CreatedAt), unixNanoOrZero(entry.AccessedAt), float64(entry.Strength), associations)
+	if err != nil {
+		return fmt.Errorf("failed to store memory: %w", err)
+	}
+
+	fts := quoteIdentifier(ftsTableName(table))
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE id = ?`, fts), entry.ID); err != nil {
+		return fmt.Errorf("failed to refresh full-text index: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (id, content) VALUES (?, ?)`, fts), entry.ID, entry.Content); err != nil {
+		return fmt.Errorf("failed to refresh full-text index: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to store memory: %w", err)
+	}
+
+	slog.Debug("Stored memory", "id", entry.ID, "type", entry.Type, "collection", table)
+	return nil
+}
+
+// Query performs a cosine similarity search over the stored embedding blobs
+func (smc *sqliteMemoryCollection) Query(ctx context.Context, memType models.MemoryType, vector []float32, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateVectorDimension(smc.config, vector); err != nil {
+		return nil, fmt.Errorf("failed to search collection %s: %w", table, err)
+	}
+
+	pushed := newSQLiteFilter(filter, "")
+	rows, err := smc.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s%s`, sqliteMemoryColumns, quoteIdentifier(table), pushed.where()),
+		pushed.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search collection %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	type scoredEntry struct {
+		entry *models.MemoryEntry
+		score float64
+	}
+
+	var scored []scoredEntry
+	for rows.Next() {
+		entry, err := scanMemoryEntry(rows)
+		if err != nil {
+			slog.Warn("Failed to convert row to memory entry", "error", err)
+			continue
+		}
+		if !MatchesFilter(entry, filter) {
+			continue
+		}
+		scored = append(scored, scoredEntry{
+			entry: entry,
+			score: cosineSimilarity(vector, entry.Embedding),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search collection %s: %w", table, err)
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].score == scored[j].score {
+			return scored[i].entry.ID < scored[j].entry.ID
+		}
+		return scored[i].score > scored[j].score
+	})
+
+	if uint64(len(scored)) > limit {
+		scored = scored[:limit]
+	}
+
+	entries := make([]*models.MemoryEntry, len(scored))
+	for i, s := range scored {
+		s.entry.Similarity = s.score
+		entries[i] = s.entry
+	}
+
+	return entries, nil
+}
+
+// KeywordQuery performs a BM25-ranked FTS5 search for a specific memory type.
+// Filter conditions SQL cannot express are evaluated on the ranked rows, in which case
+// the SQL limit is lifted.
+func (smc *sqliteMemoryCollection) KeywordQuery(ctx context.Context, memType models.MemoryType, query string, limit uint64, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	match := buildFTSQuery(query)
+	if match == "" {
+		return []*models.MemoryEntry{}, nil
+	}
+
+	fts := quoteIdentifier(ftsTableName(table))
+	columns := "m." + strings.ReplaceAll(sqliteMemoryColumns, ", ", ", m.")
+
+	pushed := newSQLiteFilter(filter, "m.")
+	args := append(append([]any{match}, pushed.args...), pushed.limit(limit))
+
+	// bm25() is lower for better matches, so ascending order ranks best first
+	rows, err := smc.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s f JOIN %s m ON m.id = f.id WHERE %s MATCH ?%s ORDER BY bm25(%s) LIMIT ?`,
+			columns, fts, quoteIdentifier(table), fts, pushed.and(), fts),
+		args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to keyword search collection %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	return collectMatchingEntries(rows, filter, limit)
+}
+
+// Retrieve gets a specific memory entry by ID
+func (smc *sqliteMemoryCollection) Retrieve(ctx context.Context, memType models.MemoryType, id string) (*models.MemoryEntry, error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	row := smc.db.QueryRowContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s WHERE id = ?`, sqliteMemoryColumns, quoteIdentifier(table)), id)
+
+	entry, err := scanMemoryEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrMemoryNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve memory: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetRecent retrieves recent memories by creation time without similarity search
+func (smc *sqliteMemoryCollection) GetRecent(ctx context.Context, memType models.MemoryType, limit uint32, filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return nil, err
+	}
+
+	pushed := newSQLiteFilter(filter, "")
+	rows, err := smc.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s%s ORDER BY created_at DESC, id LIMIT ?`, sqliteMemoryColumns, quoteIdentifier(table), pushed.where()),
+		append(pushed.args, pushed.limit(uint64(limit)))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scroll collection %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	return collectMatchingEntries(rows, filter, uint64(limit))
+}
+
+// Count returns the number of memories of a specific type
+func (smc *sqliteMemoryCollection) Count(ctx context.Context, memType models.MemoryType) (uint64, error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return 0, err
+	}
+
+	var count uint64
+	if err := smc.db.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, quoteIdentifier(table))).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count collection %s: %w", table, err)
+	}
+
+	return count, nil
+}
+
+// Delete removes memories and their full-text rows by ID
+func (smc *sqliteMemoryCollection) Delete(ctx context.Context, memType models.MemoryType, ids []string) error {
+	table, err := smc.table(memType)
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil // Nothing to delete
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	tx, err := smc.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, target := range []string{table, ftsTableName(table)} {
+		if _, err := tx.ExecContext(ctx,
+			fmt.Sprintf(`DELETE FROM %s WHERE id IN (%s)`, quoteIdentifier(target), placeholders), args...); err != nil {
+			return fmt.Errorf("failed to delete points from collection %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete points from collection %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// GetAll retrieves all memories with keyset pagination ordered newest first.
+// The cursor is the position of the last entry of the previous page.
+func (smc *sqliteMemoryCollection) GetAll(ctx context.Context, memType models.MemoryType, cursor string, limit uint32) (entries []*models.MemoryEntry, nextCursor string, err error) {
+	table, err := smc.table(memType)
+	if err != nil {
+		return nil, "", err
+	}
+
+	quoted := quoteIdentifier(table)
+	query := fmt.Sprintf(`SELECT %s FROM %s ORDER BY created_at DESC, id LIMIT ?`, sqliteMemoryColumns, quoted)
+	args := []any{int64(limit) + 1}
+
+	if cursor != "" {
+		position, err := parsePageCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		query = fmt.Sprintf(`SELECT %s FROM %s
+			WHERE created_at < ? OR (created_at = ? AND id > ?)
+			ORDER BY created_at DESC, id LIMIT ?`, sqliteMemoryColumns, quoted)
+		nanos := unixNanoOrZero(position.createdAt)
+		args = []any{nanos, nanos, position.id, int64(limit) + 1}
+	}
+
+	rows, err := smc.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to scroll collection %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	entries, err = collectMemoryEntries(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// One extra row was requested to detect whether another page exists
+	if len(entries) > int(limit) {
+		entries = entries[:limit]
+		if len(entries) > 0 {
+			last := entries[len(entries)-1]
+			nextCursor = pageCursor{createdAt: last.CreatedAt, id: last.ID}.String()
+		}
+	}
+
+	return entries, nextCursor, nil
+}
+
+// collectMemoryEntries scans all memory rows, skipping rows that fail to convert
+func collectMemoryEntries(rows *sql.Rows) ([]*models.MemoryEntry, error) {
+	entries := make([]*models.MemoryEntry, 0)
+	for rows.Next() {
+		entry, err := scanMemoryEntry(rows)
+		if err != nil {
+			slog.Warn("Failed to convert row to memory entry", "error", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read memory rows: %w", err)
+	}
+	return entries, nil
+}
+
+// collectMatchingEntries scans memory rows in order and keeps up to limit entries that match the filter
+func collectMatchingEntries(rows *sql.Rows, filter *models.MemoryFilter, limit uint64) ([]*models.MemoryEntry, error) {
+	entries := make([]*models.MemoryEntry, 0)
+	for uint64(len(entries)) < limit && rows.Next() {
+		entry, err := scanMemoryEntry(rows)
+		if err != nil {
+			slog.Warn("Failed to convert row to memory entry", "error", err)
+			continue
+		}
+		if MatchesFilter(entry, filter) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read memory rows: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,434 @@
+package vectordb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// sqliteIdentifierPattern restricts table names to plain identifiers
+var sqliteIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validSQLiteIdentifier reports whether a collection name can safely be used as a table name
+func validSQLiteIdentifier(name string) bool {
+	return sqliteIdentifierPattern.MatchString(name)
+}
+
+// quoteIdentifier quotes a validated identifier for use in SQL statements
+func quoteIdentifier(name string) string {
+	return `"` + name + `"`
+}
+
+// ftsTableName returns the FTS5 companion table for a memory table
+func ftsTableName(table string) string {
+	return table + "_fts"
+}
+
+// createSQLiteMemoryTable creates a memory table, its created_at and source indexes and FTS5 companion
+func createSQLiteMemoryTable(ctx context.Context, db *sql.DB, table string) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id              TEXT PRIMARY KEY,
+			type            TEXT NOT NULL,
+			content         TEXT NOT NULL,
+			embedding       BLOB,
+			metadata        TEXT NOT NULL DEFAULT '{}',
+			created_at      INTEGER NOT NULL,
+			accessed_at     INTEGER NOT NULL DEFAULT 0,
+			strength        REAL NOT NULL DEFAULT 0,
+			association_ids TEXT NOT NULL DEFAULT '[]'
+		)`, quoteIdentifier(table)),
+		// Index created_at to support GetRecent() ordering
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (created_at DESC, id)`,
+			quoteIdentifier("idx_"+table+"_created_at"), quoteIdentifier(table)),
+		// Index the source so filtered queries pushed into SQL do not scan the table
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (json_extract(metadata, '$.source'))`,
+			quoteIdentifier("idx_"+table+"_source"), quoteIdentifier(table)),
+		fmt.Sprintf(`CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(id UNINDEXED, content, tokenize = 'unicode61')`,
+			quoteIdentifier(ftsTableName(table))),
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSQLiteAssociationTable creates the relational association table with lookup indexes
+func createSQLiteAssociationTable(ctx context.Context, db *sql.DB, table string) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id         TEXT PRIMARY KEY,
+			source_id  TEXT NOT NULL,
+			target_id  TEXT NOT NULL,
+			type       TEXT NOT NULL,
+			strength   REAL NOT NULL,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			metadata   TEXT NOT NULL DEFAULT '{}'
+		)`, quoteIdentifier(table)),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (source_id)`,
+			quoteIdentifier("idx_"+table+"_source_id"), quoteIdentifier(table)),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (target_id)`,
+			quoteIdentifier("idx_"+table+"_target_id"), quoteIdentifier(table)),
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createSQLiteVersionTable creates the memory version table with a lookup index by memory
+func createSQLiteVersionTable(ctx context.Context, db *sql.DB, table string) error {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id        TEXT PRIMARY KEY,
+			memory_id TEXT NOT NULL,
+			version   INTEGER NOT NULL,
+			content   TEXT NOT NULL,
+			metadata  TEXT NOT NULL DEFAULT '{}',
+			edited_at INTEGER NOT NULL,
+			edited_by TEXT NOT NULL DEFAULT ''
+		)`, quoteIdentifier(table)),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s (memory_id, version)`,
+			quoteIdentifier("idx_"+table+"_memory_id"), quoteIdentifier(table)),
+	}
+
+	for _, statement := range statements {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeEmbedding serializes a vector as little-endian float32 values
+func encodeEmbedding(embedding []float32) []byte {
+	buf := make([]byte, 4*len(embedding))
+	for i, v := range embedding {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(v))
+	}
+	return buf
+}
+
+// decodeEmbedding deserializes a blob produced by encodeEmbedding
+func decodeEmbedding(blob []byte) []float32 {
+	if len(blob) == 0 {
+		return nil
+	}
+	embedding := make([]float32, len(blob)/4)
+	for i := range embedding {
+		embedding[i] = math.Float32frombits(binary.LittleEndian.Uint32(blob[i*4:]))
+	}
+	return embedding
+}
+
+// encodeJSON marshals a value for storage in a TEXT column
+func encodeJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// decodeMetadata unmarshals a metadata column, keeping integers as int64 like the Qdrant payload
+func decodeMetadata(data string) (map[string]any, error) {
+	metadata := make(map[string]any)
+	if data == "" {
+		return metadata, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, err
+	}
+
+	for key, value := range metadata {
+		metadata[key] = normalizeJSONNumber(value)
+	}
+	return metadata, nil
+}
+
+// normalizeJSONNumber converts json.Number values to int64 or float64
+func normalizeJSONNumber(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	case []any:
+		for i := range v {
+			v[i] = normalizeJSONNumber(v[i])
+		}
+		return v
+	case map[string]any:
+		for key := range v {
+			v[key] = normalizeJSONNumber(v[key])
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// unixNanoOrZero converts a time to Unix nanoseconds, keeping the zero time as 0
+func unixNanoOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// timeFromUnixNano converts stored Unix nanoseconds back to a time, keeping 0 as the zero time
+func timeFromUnixNano(nanos int64) time.Time {
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// sqliteRowScanner abstracts *sql.Row and *sql.Rows
+type sqliteRowScanner interface {
+	Scan(dest ...any) error
+}
+
+// sqliteMemoryColumns lists memory columns in the order scanMemoryEntry expects
+const sqliteMemoryColumns = "id, type, content, embedding, metadata, created_at, accessed_at, strength, association_ids"
+
+// scanMemoryEntry converts a memory row to a memory entry
+func scanMemoryEntry(row sqliteRowScanner) (*models.MemoryEntry, error) {
+	var (
+		entry          models.MemoryEntry
+		memType        string
+		embedding      []byte
+		metadata       string
+		createdAt      int64
+		accessedAt     int64
+		strength       float64
+		associationIDs string
+	)
+
+	if err := row.Scan(&entry.ID, &memType, &entry.Content, &embedding, &metadata, &createdAt, &accessedAt, &strength, &associationIDs); err != nil {
+		return nil, err
+	}
+
+	entry.Type = models.MemoryType(memType)
+	entry.Embedding = decodeEmbedding(embedding)
+	entry.CreatedAt = timeFromUnixNano(createdAt)
+	entry.AccessedAt = timeFromUnixNano(accessedAt)
+	entry.Strength = float32(strength)
+
+	var err error
+	if entry.Metadata, err = decodeMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata for memory %s: %w", entry.ID, err)
+	}
+	if err := json.Unmarshal([]byte(associationIDs), &entry.AssociationIDs); err != nil {
+		return nil, fmt.Errorf("failed to decode association IDs for memory %s: %w", entry.ID, err)
+	}
+
+	return &entry, nil
+}
+
+// sqliteAssociationColumns lists association columns in the order scanAssociation expects
+const sqliteAssociationColumns = "id, source_id, target_id, type, strength, created_at, updated_at, metadata"
+
+// scanAssociation converts an association row to a memory association
+func scanAssociation(row sqliteRowScanner) (*models.MemoryAssociation, error) {
+	var (
+		association models.MemoryAssociation
+		assocType   string
+		createdAt   int64
+		updatedAt   int64
+		metadata    string
+	)
+
+	if err := row.Scan(&association.ID, &association.SourceID, &association.TargetID, &assocType, &association.Strength, &createdAt, &updatedAt, &metadata); err != nil {
+		return nil, err
+	}
+
+	association.Type = models.AssociationType(assocType)
+	association.CreatedAt = timeFromUnixNano(createdAt)
+	association.UpdatedAt = timeFromUnixNano(updatedAt)
+
+	var err error
+	if association.Metadata, err = decodeMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata for association %s: %w", association.ID, err)
+	}
+
+	return &association, nil
+}
+
+// sqliteVersionColumns lists memory version columns in the order scanMemoryVersion expects
+const sqliteVersionColumns = "id, memory_id, version, content, metadata, edited_at, edited_by"
+
+// scanMemoryVersion converts a version row to a memory version
+func scanMemoryVersion(row sqliteRowScanner) (*models.MemoryVersion, error) {
+	var (
+		version  models.MemoryVersion
+		metadata string
+		editedAt int64
+	)
+
+	if err := row.Scan(&version.ID, &version.MemoryID, &version.Version, &version.Content, &metadata, &editedAt, &version.EditedBy); err != nil {
+		return nil, err
+	}
+
+	version.EditedAt = timeFromUnixNano(editedAt)
+
+	var err error
+	if version.Metadata, err = decodeMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata for version %s: %w", version.ID, err)
+	}
+
+	return &version, nil
+}
+
+// buildFTSQuery turns free text into an FTS5 query that ORs quoted terms,
+// so identifiers and punctuation in the input never break the MATCH syntax
+func buildFTSQuery(text string) string {
+	terms := uniqueKeywords(text)
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"`
+	}
+	return strings.Join(quoted, " OR ")
+}
+
+// sqliteFilter is the part of a memory filter that SQL can evaluate: source and type
+// matches and created_at bounds in the filter's must conditions. Rows are still checked
+// with MatchesFilter afterwards, so the pushed conditions only narrow the scan.
+type sqliteFilter struct {
+	clauses []string
+	args    []any
+	exact   bool // Every condition was pushed, so SQL alone decides which rows match
+}
+
+// newSQLiteFilter pushes what it can of a memory filter into SQL. prefix qualifies
+// column names for joined queries.
+func newSQLiteFilter(filter *models.MemoryFilter, prefix string) sqliteFilter {
+	pushed := sqliteFilter{exact: true}
+	if filter.IsEmpty() {
+		return pushed
+	}
+	pushed.exact = len(filter.Should) == 0 && len(filter.MustNot) == 0
+
+	for _, condition := range filter.Must {
+		clause, args, ok := sqliteCondition(condition, prefix)
+		if !ok {
+			pushed.exact = false
+			continue
+		}
+		pushed.clauses = append(pushed.clauses, clause)
+		pushed.args = append(pushed.args, args...)
+	}
+	return pushed
+}
+
+// where returns the pushed conditions as a WHERE clause, or "" when there are none
+func (f sqliteFilter) where() string {
+	if len(f.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(f.clauses, " AND ")
+}
+
+// and returns the pushed conditions for appending to an existing WHERE clause
+func (f sqliteFilter) and() string {
+	if len(f.clauses) == 0 {
+		return ""
+	}
+	return " AND " + strings.Join(f.clauses, " AND ")
+}
+
+// limit returns the SQL row limit for a query. Filters SQL cannot fully evaluate scan
+// until enough rows match, so they are unbounded (-1 means no limit in SQLite).
+func (f sqliteFilter) limit(limit uint64) int64 {
+	if !f.exact {
+		return -1
+	}
+	return int64(limit)
+}
+
+// sqliteCondition translates a single condition into SQL when it has an exact equivalent
+func sqliteCondition(condition models.FilterCondition, prefix string) (string, []any, bool) {
+	var column string
+	switch condition.Field {
+	case "type":
+		column = prefix + "type"
+	case "source":
+		column = "json_extract(" + prefix + "metadata, '$.source')"
+	case "created_at":
+		if condition.Range == nil {
+			return "", nil, false
+		}
+		return sqliteCreatedAtRange(prefix+"created_at", condition.Range)
+	default:
+		return "", nil, false
+	}
+
+	// Only strings compare the same way in SQL and in MatchesFilter
+	values := condition.In
+	if condition.Equals != nil {
+		values = []any{condition.Equals}
+	}
+	if len(values) == 0 {
+		return "", nil, false
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return "", nil, false
+		}
+	}
+
+	if len(values) == 1 {
+		return column + " = ?", values, true
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return column + " IN (" + placeholders + ")", values, true
+}
+
+// sqliteCreatedAtRange converts Unix-second bounds to the nanosecond created_at column.
+// A memory's created_at second is its nanoseconds rounded down, so each bound is moved to
+// the first nanosecond that satisfies it.
+func sqliteCreatedAtRange(column string, r *models.RangeFilter) (string, []any, bool) {
+	var clauses []string
+	var args []any
+	bound := func(op string, seconds float64) {
+		clauses = append(clauses, column+" "+op+" ?")
+		args = append(args, int64(seconds)*int64(time.Second))
+	}
+
+	if r.GT != nil {
+		bound(">=", math.Floor(*r.GT)+1)
+	}
+	if r.GTE != nil {
+		bound(">=", math.Ceil(*r.GTE))
+	}
+	if r.LT != nil {
+		bound("<", math.Ceil(*r.LT))
+	}
+	if r.LTE != nil {
+		bound("<", math.Floor(*r.LTE)+1)
+	}
+	return strings.Join(clauses, " AND "), args, len(clauses) > 0
+}
@@ -0,0 +1,76 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	"github.com/google/uuid"
+)
+
+// sqliteVersionCollection implements VersionCollection with a relational table
+type sqliteVersionCollection struct {
+	db    *sql.DB
+	table string
+}
+
+// newSQLiteVersionCollection creates a new SQLite version collection
+func newSQLiteVersionCollection(db *sql.DB, table string) *sqliteVersionCollection {
+	return &sqliteVersionCollection{
+		db:    db,
+		table: table,
+	}
+}
+
+// Store saves a memory version
+func (svc *sqliteVersionCollection) Store(ctx context.Context, version *models.MemoryVersion) error {
+	if version.ID == "" {
+		version.ID = uuid.New().String()
+	}
+
+	metadata, err := encodeJSON(version.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode version metadata: %w", err)
+	}
+
+	if _, err := svc.db.ExecContext(ctx,
+		fmt.Sprintf(`INSERT OR REPLACE INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?)`, quoteIdentifier(svc.table), sqliteVersionColumns),
+		version.ID, version.MemoryID, version.Version, version.Content, metadata,
+		unixNanoOrZero(version.EditedAt), version.EditedBy); err != nil {
+		return fmt.Errorf("failed to store memory version: %w", err)
+	}
+
+	return nil
+}
+
+// GetByMemoryID retrieves every version of a memory ordered by version number
+func (svc *sqliteVersionCollection) GetByMemoryID(ctx context.Context, memoryID string) ([]*models.MemoryVersion, error) {
+	rows, err := svc.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT %s FROM %s WHERE memory_id = ? ORDER BY version`, sqliteVersionColumns, quoteIdentifier(svc.table)),
+		memoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions for memory %s: %w", memoryID, err)
+	}
+	defer rows.Close()
+
+	var versions []*models.MemoryVersion
+	for rows.Next() {
+		version, err := scanMemoryVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan memory version: %w", err)
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// DeleteByMemoryID removes every version of a memory
+func (svc *sqliteVersionCollection) DeleteByMemoryID(ctx context.Context, memoryID string) error {
+	if _, err := svc.db.ExecContext(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE memory_id = ?`, quoteIdentifier(svc.table)), memoryID); err != nil {
+		return fmt.Errorf("failed to delete versions for memory %s: %w", memoryID, err)
+	}
+	return nil
+}
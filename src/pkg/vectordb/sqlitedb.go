@@ -0,0 +1,120 @@
+package vectordb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDB implements vector database operations on a single embedded SQLite file.
+// Memories live in one table per memory type with an FTS5 companion table for
+// BM25 keyword search; embeddings are stored as blobs and compared in process.
+type SQLiteDB struct {
+	db                *sql.DB
+	config            *config.VectorDBConfig
+	memoryCollections map[models.MemoryType]string
+	memories          *sqliteMemoryCollection
+	associations      *sqliteAssociationCollection
+	versions          *sqliteVersionCollection
+}
+
+// NewSQLiteDB opens (or creates) the SQLite database referenced by the configured URL.
+// The URL may be a plain file path or a "file:" DSN.
+func NewSQLiteDB(config *config.VectorDBConfig) (*SQLiteDB, error) {
+	for _, collectionName := range memoryCollectionNames(config) {
+		if !validSQLiteIdentifier(collectionName) {
+			return nil, fmt.Errorf("invalid collection name for sqlite: %s", collectionName)
+		}
+	}
+	if !validSQLiteIdentifier(config.AssociationsCollection) {
+		return nil, fmt.Errorf("invalid association collection name for sqlite: %s", config.AssociationsCollection)
+	}
+	if !validSQLiteIdentifier(config.VersionsCollection) {
+		return nil, fmt.Errorf("invalid version collection name for sqlite: %s", config.VersionsCollection)
+	}
+
+	dsn := config.URL
+	if !strings.HasPrefix(dsn, "file:") {
+		if dir := filepath.Dir(dsn); dir != "." {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, fmt.Errorf("failed to create sqlite data directory: %w", err)
+			}
+		}
+		dsn = fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)", dsn)
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	// A single connection serializes writers and keeps in-memory DSNs consistent
+	db.SetMaxOpenConns(1)
+
+	sdb := &SQLiteDB{
+		db:                db,
+		config:            config,
+		memoryCollections: memoryCollectionNames(config),
+	}
+
+	sdb.memories = newSQLiteMemoryCollection(db, config)
+	sdb.associations = newSQLiteAssociationCollection(db, config.AssociationsCollection)
+	sdb.versions = newSQLiteVersionCollection(db, config.VersionsCollection)
+
+	return sdb, nil
+}
+
+// Initialize creates the memory, full-text, association and version tables if they do not exist
+func (sdb *SQLiteDB) Initialize(ctx context.Context) error {
+	for memType, collectionName := range sdb.memoryCollections {
+		if err := createSQLiteMemoryTable(ctx, sdb.db, collectionName); err != nil {
+			return fmt.Errorf("failed to create collection %s: %w", collectionName, err)
+		}
+		slog.Info("Initialized SQLite collection", "collection", collectionName, "type", memType)
+	}
+
+	if err := createSQLiteAssociationTable(ctx, sdb.db, sdb.config.AssociationsCollection); err != nil {
+		return fmt.Errorf("failed to create association collection %s: %w", sdb.config.AssociationsCollection, err)
+	}
+	slog.Info("Initialized SQLite association collection", "collection", sdb.config.AssociationsCollection)
+
+	if err := createSQLiteVersionTable(ctx, sdb.db, sdb.config.VersionsCollection); err != nil {
+		return fmt.Errorf("failed to create version collection %s: %w", sdb.config.VersionsCollection, err)
+	}
+	slog.Info("Initialized SQLite version collection", "collection", sdb.config.VersionsCollection)
+
+	return nil
+}
+
+// HealthCheck verifies the database file is reachable
+func (sdb *SQLiteDB) HealthCheck(ctx context.Context) error {
+	return sdb.db.PingContext(ctx)
+}
+
+// Memories returns the memory collection interface
+func (sdb *SQLiteDB) Memories() MemoryCollection {
+	return sdb.memories
+}
+
+// Associations returns the association collection interface
+func (sdb *SQLiteDB) Associations() AssociationCollection {
+	return sdb.associations
+}
+
+// Versions returns the memory version collection interface
+func (sdb *SQLiteDB) Versions() VersionCollection {
+	return sdb.versions
+}
+
+// Close releases the underlying database handle
+func (sdb *SQLiteDB) Close() error {
+	return sdb.db.Close()
+}
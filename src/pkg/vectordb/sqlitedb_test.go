@@ -0,0 +1,175 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// newTestDBAt opens and initializes a SQLite database at path
+func newTestDBAt(t *testing.T, path string) *SQLiteDB {
+	t.Helper()
+
+	db, err := NewSQLiteDB(testConfig("sqlite", path))
+	if err != nil {
+		t.Fatalf("failed to create sqlite database: %v", err)
+	}
+	if err := db.Initialize(context.Background()); err != nil {
+		t.Fatalf("failed to initialize sqlite database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestSQLiteKeywordQuery(t *testing.T) {
+	db := newTestDB(t, "sqlite")
+	ctx := context.Background()
+
+	now := time.Now()
+	storeTestMemory(t, db, "timeout", "the migration timed out, so the migration timeout was raised", now, nil)
+	storeTestMemory(t, db, "deploy", "the deploy ran the migration", now, nil)
+	storeTestMemory(t, db, "lunch", "lunch was sandwiches", now, nil)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string // Expected IDs in rank order
+	}{
+		{name: "ranked by relevance", query: "migration timeout", want: []string{"timeout", "deploy"}},
+		{name: "punctuation in the query", query: `deploy.yaml "migration" (v2)`, want: []string{"deploy", "timeout"}},
+		{name: "no matching terms", query: "database", want: []string{}},
+		{name: "no terms at all", query: "?!", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := db.Memories().KeywordQuery(ctx, models.TypeEpisodic, tt.query, 10, nil)
+			if err != nil {
+				t.Fatalf("failed to search: %v", err)
+			}
+
+			got := make([]string, len(entries))
+			for i, entry := range entries {
+				got[i] = entry.ID
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("query %q returned %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteFilteredQueriesFillTheLimit(t *testing.T) {
+	db := newTestDB(t, "sqlite")
+	ctx := context.Background()
+
+	// Only every third memory is in project "a", and the newest ones are not
+	base := time.Now().Truncate(time.Second)
+	for i := 0; i < 30; i++ {
+		project, source := "b", "editor"
+		if i%3 == 2 {
+			project = "a"
+		}
+		if i >= 15 {
+			source = "terminal"
+		}
+		storeTestMemory(t, db, fmt.Sprintf("memory-%02d", i), fmt.Sprintf("note %d about the build", i),
+			base.Add(-time.Duration(i)*time.Second), map[string]any{"project": project, "source": source})
+	}
+
+	since := float64(base.Add(-20 * time.Second).Unix())
+	filters := []struct {
+		name   string
+		filter *models.MemoryFilter
+	}{
+		{
+			name:   "pushed source",
+			filter: &models.MemoryFilter{Must: []models.FilterCondition{{Field: "source", Equals: "terminal"}}},
+		},
+		{
+			name:   "pushed created_at range",
+			filter: &models.MemoryFilter{Must: []models.FilterCondition{{Field: "created_at", Range: &models.RangeFilter{LT: &since}}}},
+		},
+		{
+			name:   "metadata only",
+			filter: &models.MemoryFilter{Must: []models.FilterCondition{{Field: "project", Equals: "a"}}},
+		},
+		{
+			name: "pushed and metadata",
+			filter: &models.MemoryFilter{Must: []models.FilterCondition{
+				{Field: "source", Equals: "editor"},
+				{Field: "project", Equals: "a"},
+			}},
+		},
+		{
+			name:   "must not",
+			filter: &models.MemoryFilter{MustNot: []models.FilterCondition{{Field: "project", Equals: "b"}}},
+		},
+	}
+
+	const limit = 3
+	queries := []struct {
+		name string
+		run  func(filter *models.MemoryFilter) ([]*models.MemoryEntry, error)
+	}{
+		{name: "recent", run: func(filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+			return db.Memories().GetRecent(ctx, models.TypeEpisodic, limit, filter)
+		}},
+		{name: "keyword", run: func(filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+			return db.Memories().KeywordQuery(ctx, models.TypeEpisodic, "build", limit, filter)
+		}},
+		{name: "vector", run: func(filter *models.MemoryFilter) ([]*models.MemoryEntry, error) {
+			return db.Memories().Query(ctx, models.TypeEpisodic, []float32{1, 0, 0, 0}, limit, filter)
+		}},
+	}
+
+	for _, query := range queries {
+		for _, tt := range filters {
+			t.Run(query.name+"/"+tt.name, func(t *testing.T) {
+				entries, err := query.run(tt.filter)
+				if err != nil {
+					t.Fatalf("failed to query: %v", err)
+				}
+				if len(entries) != limit {
+					t.Errorf("returned %d memories, want %d", len(entries), limit)
+				}
+				for _, entry := range entries {
+					if !MatchesFilter(entry, tt.filter) {
+						t.Errorf("memory %s does not match the filter", entry.ID)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSQLiteReopenKeepsMemories(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "memories.db")
+
+	db := newTestDBAt(t, path)
+	storeTestMemory(t, db, "kept", "a memory that survives a restart", time.Now(), map[string]any{"source": "editor"})
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	reopened := newTestDBAt(t, path)
+	entry, err := reopened.Memories().Retrieve(ctx, models.TypeEpisodic, "kept")
+	if err != nil {
+		t.Fatalf("failed to retrieve memory after reopening: %v", err)
+	}
+	if entry.Metadata["source"] != "editor" || len(entry.Embedding) != testDim {
+		t.Errorf("reopened memory has metadata %v and %d dimensions", entry.Metadata, len(entry.Embedding))
+	}
+
+	matches, err := reopened.Memories().KeywordQuery(ctx, models.TypeEpisodic, "restart", 10, nil)
+	if err != nil || len(matches) != 1 {
+		t.Errorf("keyword search after reopening returned %d matches (%v), want 1", len(matches), err)
+	}
+}
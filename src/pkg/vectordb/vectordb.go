@@ -2,11 +2,16 @@ package vectordb
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
 )
 
+// ErrMemoryNotFound is returned when a memory ID does not exist in the requested collection
+var ErrMemoryNotFound = errors.New("memory not found")
+
 // VectorDB defines the interface for vector database operations
 type VectorDB interface {
 	// Initialize sets up the vector database (collections, etc.)
@@ -20,6 +25,9 @@ type VectorDB interface {
 	
 	// Associations returns the association collection interface
 	Associations() AssociationCollection
+	
+	// Versions returns the memory version collection interface
+	Versions() VersionCollection
 }
 
 // NewVectorDB creates a new VectorDB implementation based on the provider
@@ -27,7 +35,24 @@ func NewVectorDB(config *config.VectorDBConfig) (VectorDB, error) {
 	switch config.Provider {
 	case "qdrant":
 		return NewQdrantDB(config)
+	case "memory":
+		return NewInMemoryDB(config)
+	case "sqlite":
+		return NewSQLiteDB(config)
 	default:
 		return nil, fmt.Errorf("unsupported vector database provider: %s", config.Provider)
 	}
-}
\ No newline at end of file
+}
+
+// memoryCollectionNames maps each stored memory type to its collection name. Chunks of
+// long memories are kept in their own collection when one is configured.
+func memoryCollectionNames(config *config.VectorDBConfig) map[models.MemoryType]string {
+	collections := make(map[models.MemoryType]string, len(config.MemoryCollections)+1)
+	for memType, collectionName := range config.MemoryCollections {
+		collections[models.MemoryType(memType)] = collectionName
+	}
+	if config.ChunksCollection != "" {
+		collections[models.TypeChunk] = config.ChunksCollection
+	}
+	return collections
+}
@@ -0,0 +1,182 @@
+package vectordb
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/JaimeStill/persistent-context/pkg/config"
+	"github.com/JaimeStill/persistent-context/pkg/models"
+)
+
+// testProviders are the embedded providers the collection tests run against
+var testProviders = []string{"memory", "sqlite"}
+
+const testDim = 4
+
+// testConfig configures a provider with episodic and semantic collections
+func testConfig(provider, url string) *config.VectorDBConfig {
+	return &config.VectorDBConfig{
+		Provider:        provider,
+		URL:             url,
+		VectorDimension: testDim,
+		MemoryCollections: map[string]string{
+			"episodic": "episodic_memories",
+			"semantic": "semantic_memories",
+		},
+		AssociationsCollection: "associations",
+		VersionsCollection:     "memory_versions",
+	}
+}
+
+// newTestDB creates and initializes an embedded vector database
+func newTestDB(t *testing.T, provider string) VectorDB {
+	t.Helper()
+
+	db, err := NewVectorDB(testConfig(provider, filepath.Join(t.TempDir(), "memories.db")))
+	if err != nil {
+		t.Fatalf("failed to create %s database: %v", provider, err)
+	}
+	if err := db.Initialize(context.Background()); err != nil {
+		t.Fatalf("failed to initialize %s database: %v", provider, err)
+	}
+	if closer, ok := db.(interface{ Close() error }); ok {
+		t.Cleanup(func() { closer.Close() })
+	}
+
+	return db
+}
+
+// storeTestMemory stores an episodic memory created at the given time
+func storeTestMemory(t *testing.T, db VectorDB, id, content string, createdAt time.Time, metadata map[string]any) {
+	t.Helper()
+
+	entry := &models.MemoryEntry{
+		ID:         id,
+		Type:       models.TypeEpisodic,
+		Content:    content,
+		Embedding:  []float32{1, 0, 0, 0},
+		Metadata:   metadata,
+		CreatedAt:  createdAt,
+		AccessedAt: createdAt,
+		Strength:   1,
+	}
+	if err := db.Memories().Store(context.Background(), entry); err != nil {
+		t.Fatalf("failed to store memory %s: %v", id, err)
+	}
+}
+
+// testIDs returns n IDs in ascending order
+func testIDs(prefix string, n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%s-%02d", prefix, i)
+	}
+	return ids
+}
+
+// pageAll collects every ID a paging function returns, deleting the last item of the
+// first page before the second is requested
+func pageAll(t *testing.T, page func(cursor string) ([]string, string, error), deleteID func(id string) error) (ids []string, deleted string) {
+	t.Helper()
+
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages > 100 {
+			t.Fatal("paging did not finish")
+		}
+
+		items, next, err := page(cursor)
+		if err != nil {
+			t.Fatalf("failed to get page %d: %v", pages+1, err)
+		}
+		ids = append(ids, items...)
+
+		if pages == 0 && len(items) > 0 {
+			deleted = items[len(items)-1]
+			if err := deleteID(deleted); err != nil {
+				t.Fatalf("failed to delete %s: %v", deleted, err)
+			}
+		}
+		if next == "" {
+			return ids, deleted
+		}
+		cursor = next
+	}
+}
+
+func TestMemoryGetAllSurvivesDeletedCursor(t *testing.T) {
+	for _, provider := range testProviders {
+		t.Run(provider, func(t *testing.T) {
+			db := newTestDB(t, provider)
+			ctx := context.Background()
+
+			// Pairs of memories share a creation time so the ID breaks ties
+			base := time.Now().Truncate(time.Second)
+			ids := testIDs("memory", 9)
+			for i, id := range ids {
+				storeTestMemory(t, db, id, "content "+id, base.Add(-time.Duration(i/2)*time.Second), nil)
+			}
+
+			got, deleted := pageAll(t, func(cursor string) ([]string, string, error) {
+				entries, next, err := db.Memories().GetAll(ctx, models.TypeEpisodic, cursor, 2)
+				page := make([]string, len(entries))
+				for i, entry := range entries {
+					page[i] = entry.ID
+				}
+				return page, next, err
+			}, func(id string) error {
+				return db.Memories().Delete(ctx, models.TypeEpisodic, []string{id})
+			})
+
+			if !slices.Equal(got, ids) {
+				t.Errorf("paged %v after deleting %s, want %v", got, deleted, ids)
+			}
+		})
+	}
+}
+
+func TestAssociationGetAllSurvivesDeletedCursor(t *testing.T) {
+	for _, provider := range testProviders {
+		t.Run(provider, func(t *testing.T) {
+			db := newTestDB(t, provider)
+			ctx := context.Background()
+
+			base := time.Now().Truncate(time.Second)
+			ids := testIDs("association", 9)
+			for i, id := range ids {
+				createdAt := base.Add(time.Duration(i/2) * time.Second)
+				err := db.Associations().Store(ctx, &models.MemoryAssociation{
+					ID:        id,
+					SourceID:  "source",
+					TargetID:  fmt.Sprintf("target-%d", i),
+					Type:      models.AssociationSemantic,
+					Strength:  0.5,
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				})
+				if err != nil {
+					t.Fatalf("failed to store association %s: %v", id, err)
+				}
+			}
+
+			got, deleted := pageAll(t, func(cursor string) ([]string, string, error) {
+				associations, next, err := db.Associations().GetAll(ctx, cursor, 2)
+				page := make([]string, len(associations))
+				for i, association := range associations {
+					page[i] = association.ID
+				}
+				return page, next, err
+			}, func(id string) error {
+				return db.Associations().Delete(ctx, []string{id})
+			})
+
+			if !slices.Equal(got, ids) {
+				t.Errorf("paged %v after deleting %s, want %v", got, deleted, ids)
+			}
+		})
+	}
+}